@@ -1,10 +1,14 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 	"time"
 
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
@@ -12,29 +16,52 @@ import (
 	"k8s.io/klog"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/apimachinery/pkg/util/wait"
+	kubeinformers "k8s.io/client-go/informers"
 	appsv1informer "k8s.io/client-go/informers/apps/v1"
+	autoscalingv1informer "k8s.io/client-go/informers/autoscaling/v1"
 	corev1informer "k8s.io/client-go/informers/core/v1"
+	networkingv1beta1informer "k8s.io/client-go/informers/networking/v1beta1"
 	appsv1lister "k8s.io/client-go/listers/apps/v1"
+	autoscalingv1lister "k8s.io/client-go/listers/autoscaling/v1"
 	corev1lister "k8s.io/client-go/listers/core/v1"
+	networkingv1beta1lister "k8s.io/client-go/listers/networking/v1beta1"
 
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
 	clientset "github.com/dichque/grafana-operator/pkg/client/clientset/versioned"
 	"github.com/dichque/grafana-operator/pkg/client/clientset/versioned/scheme"
 	gscheme "github.com/dichque/grafana-operator/pkg/client/clientset/versioned/scheme"
 	ginformers "github.com/dichque/grafana-operator/pkg/client/informers/externalversions/grafana/v1"
 	glisters "github.com/dichque/grafana-operator/pkg/client/listers/grafana/v1"
+	"github.com/dichque/grafana-operator/pkg/conditions"
 	"github.com/dichque/grafana-operator/pkg/util"
+	"github.com/dichque/grafana-operator/pkg/validation"
 )
 
 const controllerName string = "grafana-controller"
 const maxRetries int = 3
 const aimsNSLabel string = "aims.cisco.com/kaas"
+const credentialsHashAnnotation string = "aims.cisco.com/credentials-hash"
+const configHashAnnotation string = "aims.cisco.com/config-hash"
+
+// replacedByLabel marks the replicasets of a deployment replaced to change its selector with
+// the name of the instance, replacedReplicaSetsAnnotation lists them on the replacement
+const (
+	replacedByLabel               string = "aims.cisco.com/replaced-by"
+	replacedReplicaSetsAnnotation string = "aims.cisco.com/replaced-replicasets"
+)
+const rotateAdminPasswordAnnotation string = "aims.cisco.com/rotate-admin-password"
 
 type actionType string
 
@@ -60,6 +87,31 @@ type Controller struct {
 	configMapLister corev1lister.ConfigMapLister
 	configMapSynced cache.InformerSynced
 
+	secretLister corev1lister.SecretLister
+	secretSynced cache.InformerSynced
+
+	dataSourceLister glisters.GrafanaDataSourceLister
+	dataSourceSynced cache.InformerSynced
+
+	serviceLister corev1lister.ServiceLister
+	serviceSynced cache.InformerSynced
+
+	ingressLister networkingv1beta1lister.IngressLister
+	ingressSynced cache.InformerSynced
+
+	hpaLister autoscalingv1lister.HorizontalPodAutoscalerLister
+	hpaSynced cache.InformerSynced
+
+	// Routes replace Ingresses when the cluster serves route.openshift.io
+	dynamicClient   dynamic.Interface
+	routesAvailable bool
+	routeLister     cache.GenericLister
+	routeSynced     cache.InformerSynced
+
+	// defaultImage runs resources stored without an image, when the defaulting webhook is
+	// disabled or not installed
+	defaultImage string
+
 	workqueue workqueue.RateLimitingInterface
 	recorder  record.EventRecorder
 }
@@ -70,7 +122,15 @@ func NewController(
 	grafanaClientset clientset.Interface,
 	ginformer ginformers.GrafanaInformer,
 	deploymentInformer appsv1informer.DeploymentInformer,
-	configMapInformer corev1informer.ConfigMapInformer) *Controller {
+	configMapInformer corev1informer.ConfigMapInformer,
+	secretInformer corev1informer.SecretInformer,
+	dataSourceInformer ginformers.GrafanaDataSourceInformer,
+	serviceInformer corev1informer.ServiceInformer,
+	ingressInformer networkingv1beta1informer.IngressInformer,
+	hpaInformer autoscalingv1informer.HorizontalPodAutoscalerInformer,
+	dynamicClient dynamic.Interface,
+	routeInformer kubeinformers.GenericInformer,
+	defaultImage string) *Controller {
 
 	utilruntime.Must(gscheme.AddToScheme(scheme.Scheme))
 	klog.V(4).Info("Creating event broadcaster")
@@ -88,10 +148,28 @@ func NewController(
 		deploymentSynced: deploymentInformer.Informer().HasSynced,
 		configMapLister:  configMapInformer.Lister(),
 		configMapSynced:  configMapInformer.Informer().HasSynced,
+		secretLister:     secretInformer.Lister(),
+		secretSynced:     secretInformer.Informer().HasSynced,
+		dataSourceLister: dataSourceInformer.Lister(),
+		dataSourceSynced: dataSourceInformer.Informer().HasSynced,
+		serviceLister:    serviceInformer.Lister(),
+		serviceSynced:    serviceInformer.Informer().HasSynced,
+		ingressLister:    ingressInformer.Lister(),
+		ingressSynced:    ingressInformer.Informer().HasSynced,
+		hpaLister:        hpaInformer.Lister(),
+		hpaSynced:        hpaInformer.Informer().HasSynced,
+		dynamicClient:    dynamicClient,
+		routesAvailable:  routeInformer != nil,
+		defaultImage:     defaultImage,
 		workqueue:        workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "Grafana"),
 		recorder:         recorder,
 	}
 
+	if routeInformer != nil {
+		controller.routeLister = routeInformer.Lister()
+		controller.routeSynced = routeInformer.Informer().HasSynced
+	}
+
 	klog.Info("Setting up event handlers")
 	// Set up an event handler for when Grafana resources change
 	ginformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -128,6 +206,88 @@ func NewController(
 			controller.enqueueConfigMap(obj, deleteAction)
 		},
 	})
+
+	// Set up an event handler for secrets referenced by grafana instances
+	secretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			controller.enqueueSecret(obj, addAction)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			controller.enqueueSecret(newObj, updateAction)
+		},
+		DeleteFunc: func(obj interface{}) {
+			controller.enqueueSecret(obj, deleteAction)
+		},
+	})
+
+	// Set up an event handler for the services owned by grafana instances
+	serviceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			controller.enqueueService(obj, addAction)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			controller.enqueueService(newObj, updateAction)
+		},
+		DeleteFunc: func(obj interface{}) {
+			controller.enqueueService(obj, deleteAction)
+		},
+	})
+
+	// Set up an event handler for the ingresses owned by grafana instances
+	ingressInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			controller.enqueueIngress(obj, addAction)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			controller.enqueueIngress(newObj, updateAction)
+		},
+		DeleteFunc: func(obj interface{}) {
+			controller.enqueueIngress(obj, deleteAction)
+		},
+	})
+
+	// Set up an event handler for routes, when the cluster serves them
+	if routeInformer != nil {
+		routeInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				controller.enqueueRoute(obj, addAction)
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				controller.enqueueRoute(newObj, updateAction)
+			},
+			DeleteFunc: func(obj interface{}) {
+				controller.enqueueRoute(obj, deleteAction)
+			},
+		})
+	}
+
+	// Set up an event handler for the autoscalers targeting the deployments of grafana instances
+	hpaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			controller.enqueueHPA(obj, addAction)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			controller.enqueueHPA(oldObj, updateAction)
+			controller.enqueueHPA(newObj, updateAction)
+		},
+		DeleteFunc: func(obj interface{}) {
+			controller.enqueueHPA(obj, deleteAction)
+		},
+	})
+
+	// Set up an event handler for datasources selected by grafana instances
+	dataSourceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			controller.enqueueDataSource(obj, addAction)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			controller.enqueueDataSource(oldObj, updateAction)
+			controller.enqueueDataSource(newObj, updateAction)
+		},
+		DeleteFunc: func(obj interface{}) {
+			controller.enqueueDataSource(obj, deleteAction)
+		},
+	})
 	return controller
 }
 
@@ -150,6 +310,17 @@ func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 	if ok := cache.WaitForCacheSync(stopCh, c.deploymentSynced); !ok {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
+	if ok := cache.WaitForCacheSync(stopCh, c.configMapSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+	if ok := cache.WaitForCacheSync(stopCh, c.secretSynced, c.dataSourceSynced, c.serviceSynced, c.ingressSynced, c.hpaSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+	if c.routesAvailable {
+		if ok := cache.WaitForCacheSync(stopCh, c.routeSynced); !ok {
+			return fmt.Errorf("failed to wait for caches to sync")
+		}
+	}
 
 	klog.Info("Starting workers")
 	// Launch two workers to process At resources
@@ -192,6 +363,9 @@ func (c *Controller) processNextWorkItem() bool {
 	if err == nil {
 		c.workqueue.Forget(key)
 		klog.Info("Successfully processed")
+	} else if _, ok := err.(*conflictError); ok {
+		// Retrying does not resolve an ownership conflict
+		c.workqueue.Forget(key)
 	} else if c.workqueue.NumRequeues(key) < maxRetries {
 		c.workqueue.AddRateLimited(key)
 		klog.Info("Re-processing the queue")
@@ -238,150 +412,1141 @@ func (c *Controller) reconcile(key string) error {
 
 	// Clone because the original object is owned by the lister.
 	instance := original.DeepCopy()
+	// Only used to render the deployment, status updates do not persist the spec
+	if instance.Spec.Image == "" {
+		instance.Spec.Image = c.defaultImage
+	}
+
+	// A rejected spec keeps the running configuration until it is fixed
+	if err = validation.Validate(instance).ToAggregate(); err != nil {
+		klog.Errorf("invalid spec on grafana %s: %s", key, err)
+		c.recorder.Event(instance, v1.EventTypeWarning, "InvalidSpec", err.Error())
+		// The generation is not observed until a spec is applied
+		setProgressConditions(instance, aimsv1.ConditionReasonInvalidSpec, err)
+		return c.updateStatus(original, instance)
+	}
+
+	err = c.sync(instance)
+	if conflict, ok := err.(*conflictError); ok {
+		// Not requeued, the conflict is for the user to resolve and retried on the next resync
+		klog.Errorf("conflict on grafana %s: %s", key, conflict)
+		c.setConflictCondition(instance, conflict)
+		setProgressConditions(instance, aimsv1.ConditionReasonNotOwned, conflict)
+	} else if err != nil {
+		setProgressConditions(instance, aimsv1.ConditionReasonReconcileError, err)
+	} else {
+		c.setConflictCondition(instance, nil)
+		setProgressConditions(instance, aimsv1.ConditionReasonReconciled, nil)
+	}
+	if err == nil {
+		instance.Status.ObservedGeneration = instance.Generation
+	}
+
+	if updateErr := c.updateStatus(original, instance); updateErr != nil {
+		return updateErr
+	}
+	return err
+}
+
+// updateStatus writes the status of the instance when it changed
+func (c *Controller) updateStatus(original, instance *aimsv1.Grafana) error {
+	if reflect.DeepEqual(original.Status, instance.Status) {
+		return nil
+	}
+	instance.Status.LastUpdatedTime = metav1.Now()
+	_, err := c.grafanaClientset.AimsV1().Grafanas(instance.Namespace).UpdateStatus(instance)
+	if err != nil {
+		klog.Errorf("Unable to update status of grafana instance: %s : %s", instance.Name, err)
+		return err
+	}
+	return nil
+}
+
+// sync brings the child objects of the instance in line with its spec and fills in its status
+func (c *Controller) sync(instance *aimsv1.Grafana) error {
+	err := c.ensureAdminSecret(instance)
+	if err != nil {
+		return err
+	}
+
+	if err = c.ensureSecretKeySecret(instance); err != nil {
+		return err
+	}
+
+	credentialsHash, err := c.credentialsHash(instance)
+	if err != nil {
+		return err
+	}
+
+	dataSources, err := c.selectedDataSources(instance)
+	if err != nil {
+		return err
+	}
+	external := []aimsv1.GrafanaDatasource{}
+	for _, ds := range dataSources {
+		external = append(external, ds.Spec.GrafanaDatasource)
+	}
+
+	datasourceSecret, err := c.reconcileDatasourceSecret(instance, dataSources)
+	if err != nil {
+		return err
+	}
+
+	folders, err := c.dashboardFolders(instance)
+	if err != nil {
+		return err
+	}
 
 	gCMList := &v1.ConfigMapList{}
-	cm := &v1.ConfigMap{}
-	gCMList = util.CreateConfigMap(instance, gCMList)
+	gCMList, err = util.CreateConfigMap(instance, folders, external, gCMList)
+	if err != nil {
+		return err
+	}
 
-	for _, *cm = range gCMList.Items {
-		foundCM, err := c.kubeClientset.CoreV1().ConfigMaps(cm.Namespace).Get(cm.Name, metav1.GetOptions{})
+	err = c.reconcileConfigMaps(instance, gCMList.Items)
+	setConfigMapCondition(instance, err)
+	if err != nil {
+		return err
+	}
 
-		if err != nil && errors.IsNotFound(err) {
-			_, err = c.kubeClientset.CoreV1().ConfigMaps(cm.Namespace).Create(cm)
-			if err != nil {
-				return err
-			}
-			klog.Infof("configmap created: %s", cm.Name)
-		} else if !reflect.DeepEqual(foundCM.Data, cm.Data) {
-			_, err = c.kubeClientset.CoreV1().ConfigMaps(cm.Namespace).Update(cm)
-			if err != nil {
-				return err
-			}
-			klog.Infof("configmap updated: %s", cm.Name)
-			// TODO: We may need to bounce the pods for changes to take effects
-		} else if err != nil {
-			return err
-		}
+	if err = c.reconcileDataClaim(instance); err != nil {
+		return err
+	}
+
+	// Stamp the config and credentials hashes on the pod template so that configuration and
+	// credential changes roll the pods
+	configHash := util.ConfigHash(instance, gCMList.Items, []*v1.Secret{datasourceSecret})
+	gdeploy := util.Deployment(instance, folders)
+	gdeploy.Spec.Template.Annotations = map[string]string{configHashAnnotation: configHash}
+	if credentialsHash != "" {
+		gdeploy.Spec.Template.Annotations[credentialsHashAnnotation] = credentialsHash
+	}
+	rolled, err := c.reconcileDeployment(instance, gdeploy)
+	setDeploymentCondition(instance, err)
+	if err != nil {
+		return err
+	}
+	if err = c.observeDeployment(instance, gdeploy.Name); err != nil {
+		return err
+	}
+	instance.Status.ConfigHash = configHash
+	if rolled {
+		now := metav1.Now()
+		instance.Status.LastRolloutTime = &now
 	}
 
-	gdeploy := util.Deployment(instance)
-	found, err := c.kubeClientset.AppsV1().Deployments(gdeploy.Namespace).Get(gdeploy.Name, metav1.GetOptions{})
+	if err = c.adoptLegacyConfigMaps(instance); err != nil {
+		return err
+	}
 
-	if err != nil && errors.IsNotFound(err) {
-		found, err = c.kubeClientset.AppsV1().Deployments(gdeploy.Namespace).Create(gdeploy)
-		if err != nil {
-			return err
-		}
-		klog.Infof("deployment launched: %s", gdeploy.Name)
-	} else if err != nil {
+	if err = c.reconcileService(instance); err != nil {
 		return err
-	} else if *found.Spec.Replicas != *instance.Spec.Replicas {
-		gdeploy.Spec.Replicas = instance.Spec.Replicas
-		_, err = c.kubeClientset.AppsV1().Deployments(gdeploy.Namespace).Update(gdeploy)
-		if err != nil {
-			klog.Errorf("unable to reconcile replica count: %s", err)
-		}
+	}
 
-		klog.Infof("deployment processing: available replica: count=%v", found.Status.AvailableReplicas)
-	} else {
-		// Re-queue happens automatically
+	if err = c.reconcileIngress(instance); err != nil {
+		return err
+	}
+
+	desired := desiredInventory(instance, gCMList.Items, folders, datasourceSecret, gdeploy, c.routesAvailable)
+	if err = c.prune(instance, desired); err != nil {
+		return err
+	}
+
+	return c.rotateAdminPassword(instance)
+}
+
+// rotateAdminPassword handles a rotate-admin-password request annotated on the instance.
+// Running pods are switched to the new password through the grafana API before the backing
+// secret is updated, so the secret holds working credentials throughout the rollout. With an
+// external database the password is changed once for all replicas, see adminEndpoints.
+func (c *Controller) rotateAdminPassword(grafana *aimsv1.Grafana) error {
+	request := grafana.Annotations[rotateAdminPasswordAnnotation]
+	if request == "" || request == grafana.Status.AdminPasswordRotation {
 		return nil
 	}
 
-	if !reflect.DeepEqual(original, instance) {
-		_, err = c.grafanaClientset.AimsV1().Grafanas(instance.Namespace).UpdateStatus(instance)
-		if err != nil {
-			klog.Errorf("Unable to update status of grafana instance: %s : %s", instance.Name, err)
-			return err
-		}
+	ref := util.CredentialsRef(grafana)
+	if grafana.Spec.CredentialsSecretRef == nil && grafana.Spec.Password != "" {
+		// Nothing to retry, the request is answered with a failed condition
+		grafana.Status.AdminPasswordRotation = request
+		c.setRotationCondition(grafana, fmt.Errorf("admin password is set inline in spec.password and cannot be rotated"))
+		return nil
+	}
+
+	err := c.rotateSecretPassword(grafana, ref)
+	c.setRotationCondition(grafana, err)
+	if err != nil {
+		return err
 	}
 
+	grafana.Status.AdminPasswordRotation = request
 	return nil
 }
 
-// enqueueGrafana takes a Grafana resource and converts it into a namespace/name
-// string which is then put onto the work queue. This method should *not* be
-// passed resources of any type other than Grafana.
-func (c *Controller) enqueueGrafana(obj interface{}, a actionType) {
-	var key string
-	var err error
-	action = a
-	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
-		utilruntime.HandleError(err)
-		return
+func (c *Controller) rotateSecretPassword(grafana *aimsv1.Grafana, ref *aimsv1.CredentialsSecretRef) error {
+	secret, err := c.secretLister.Secrets(grafana.Namespace).Get(ref.Name)
+	if err != nil {
+		return err
 	}
-	klog.Infof("enqueue grafana with action: %s", action)
-	c.workqueue.Add(key)
-}
+	secret = secret.DeepCopy()
 
-// enqueue a  deployment and checks that the owner reference points to an Grafana object. It then
-// enqueues this Grafana object.
-func (c *Controller) enqueueDeployment(obj interface{}, a actionType) {
-	var deploy *appsv1.Deployment
-	var ok bool
-	action = a
+	userKey, passwordKey := util.CredentialsKeys(ref)
+	user, current := string(secret.Data[userKey]), string(secret.Data[passwordKey])
+	password, err := util.RandomString(24)
+	if err != nil {
+		return err
+	}
 
-	if deploy, ok = obj.(*appsv1.Deployment); !ok {
-		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
-		if !ok {
-			utilruntime.HandleError(fmt.Errorf("error decoding deployment, invalid type"))
-			return
-		}
-		deploy, ok = tombstone.Obj.(*appsv1.Deployment)
-		if !ok {
-			utilruntime.HandleError(fmt.Errorf("error decoding deployment tombstone, invalid type"))
-			return
-		}
-		klog.V(4).Infof("Recovered deleted deployment '%s' from tombstone", deploy.GetName())
+	endpoints, err := adminEndpoints(c.kubeClientset, grafana)
+	if err != nil {
+		return err
 	}
-	if ownerRef := metav1.GetControllerOf(deploy); ownerRef != nil {
-		if ownerRef.Kind != "Grafana" {
-			return
+	if len(endpoints) == 0 {
+		return fmt.Errorf("no ready grafana pods to rotate the admin password on")
+	}
+
+	rotated := []string{}
+	rollback := func() {
+		for _, endpoint := range rotated {
+			if err := setAdminPassword(endpoint, user, password, current); err != nil {
+				klog.Errorf("unable to roll back admin password on %s: %s", endpoint, err)
+			}
 		}
+	}
 
-		grafana, err := c.gLister.Grafanas(deploy.GetNamespace()).Get(ownerRef.Name)
-		if err != nil {
-			klog.V(4).Infof("ignoring orphaned deploy '%s' of Grafana '%s'", deploy.GetSelfLink(), ownerRef.Name)
-			return
+	for _, endpoint := range endpoints {
+		if err = setAdminPassword(endpoint, user, current, password); err != nil {
+			rollback()
+			return fmt.Errorf("unable to set admin password on %s: %s", endpoint, err)
 		}
+		rotated = append(rotated, endpoint)
+	}
 
-		klog.Infof("enqueuing Grafana %s/%s because of deployment change or periodic reconcilation", grafana.Namespace, grafana.Name)
-		c.enqueueGrafana(grafana, action)
+	secret.Data[passwordKey] = []byte(password)
+	_, err = c.kubeClientset.CoreV1().Secrets(grafana.Namespace).Update(secret)
+	if err != nil {
+		rollback()
+		return err
 	}
+
+	klog.Infof("admin password rotated: %s/%s", grafana.Namespace, grafana.Name)
+	return nil
 }
 
-// enqueue a  configmap and checks that the owner reference points to an Grafana object. It then
-// enqueues this Grafana object.
-func (c *Controller) enqueueConfigMap(obj interface{}, a actionType) {
-	var cm *v1.ConfigMap
-	var ok bool
-	action = a
+// setRotationCondition records the time and outcome of an admin password rotation
+func (c *Controller) setRotationCondition(grafana *aimsv1.Grafana, err error) {
+	condition := aimsv1.GrafanaCondition{
+		Type:    aimsv1.ConditionTypeAdminPasswordRotated,
+		Status:  aimsv1.ConditionStatusTrue,
+		Reason:  aimsv1.ConditionReasonAdminPasswordRotated,
+		Message: "admin password rotated",
+	}
+	if err != nil {
+		condition.Status = aimsv1.ConditionStatusFalse
+		condition.Reason = aimsv1.ConditionReasonAdminPasswordRotationFailed
+		condition.Message = err.Error()
+		c.recorder.Eventf(grafana, v1.EventTypeWarning, string(condition.Reason), "admin password rotation failed: %s", err)
+	} else {
+		c.recorder.Event(grafana, v1.EventTypeNormal, string(condition.Reason), condition.Message)
+	}
 
-	if cm, ok = obj.(*v1.ConfigMap); !ok {
-		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
-		if !ok {
-			utilruntime.HandleError(fmt.Errorf("error decoding configmap, invalid type"))
-			return
-		}
-		cm, ok = tombstone.Obj.(*v1.ConfigMap)
-		if !ok {
-			utilruntime.HandleError(fmt.Errorf("error decoding configmap tombstone, invalid type"))
-			return
-		}
-		klog.V(4).Infof("Recovered deleted configmap '%s' from tombstone", cm.GetName())
+	// Every rotation is a transition, even when the outcome repeats
+	conditions.Remove(&grafana.Status.Conditions, condition.Type)
+	conditions.Set(&grafana.Status.Conditions, condition)
+}
+
+// ensureAdminSecret creates the operator managed admin credentials secret of an instance
+// without credentialsSecretRef, and keeps it in line with the inline spec.password. A
+// generated password is never rotated here.
+func (c *Controller) ensureAdminSecret(grafana *aimsv1.Grafana) error {
+	if !util.ManagesAdminSecret(grafana) {
+		grafana.Status.AdminSecret = ""
+		return nil
 	}
-	if ownerRef := metav1.GetControllerOf(cm); ownerRef != nil {
-		if ownerRef.Kind != "Grafana" {
-			return
-		}
 
-		grafana, err := c.gLister.Grafanas(cm.GetNamespace()).Get(ownerRef.Name)
+	name := util.AdminSecretName(grafana)
+	found, err := c.secretLister.Secrets(grafana.Namespace).Get(name)
+	if err != nil && errors.IsNotFound(err) {
+		secret, err := util.AdminSecret(grafana)
 		if err != nil {
-			klog.V(4).Infof("ignoring orphaned configmap '%s' of Grafana '%s'", cm.GetSelfLink(), ownerRef.Name)
-			return
+			return err
 		}
-
-		klog.Infof("enqueuing Grafana %s/%s because of configmap change or periodic reconcilation", grafana.Namespace, grafana.Name)
+		_, err = c.kubeClientset.CoreV1().Secrets(grafana.Namespace).Create(secret)
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+		klog.Infof("admin secret created: %s", name)
+		c.recorder.Eventf(grafana, v1.EventTypeNormal, "AdminSecretCreated", "stored admin credentials in secret %s", name)
+	} else if err != nil {
+		return err
+	} else if grafana.Spec.Password != "" {
+		// The inline password is the source of truth, a generated one is never replaced
+		updated := found.DeepCopy()
+		if util.SetAdminCredentials(grafana, updated) {
+			_, err = c.kubeClientset.CoreV1().Secrets(grafana.Namespace).Update(updated)
+			if err != nil {
+				return err
+			}
+			klog.Infof("admin secret updated: %s", name)
+		}
+	}
+
+	grafana.Status.AdminSecret = name
+	return nil
+}
+
+// ensureSecretKeySecret creates the secret_key shared by replicas on an external database
+// when no generated admin secret carries one. An existing key is never replaced as it
+// encrypts the secrets stored in the database.
+func (c *Controller) ensureSecretKeySecret(grafana *aimsv1.Grafana) error {
+	if !util.NeedsSecretKeySecret(grafana) {
+		return nil
+	}
+
+	name := util.SecretKeySecretName(grafana)
+	_, err := c.secretLister.Secrets(grafana.Namespace).Get(name)
+	if err != nil && errors.IsNotFound(err) {
+		secret, err := util.SecretKeySecret(grafana)
+		if err != nil {
+			return err
+		}
+		_, err = c.kubeClientset.CoreV1().Secrets(grafana.Namespace).Create(secret)
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+		klog.Infof("secret key secret created: %s", name)
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// reconcileConfigMaps creates or updates the configmaps rendered for the instance
+func (c *Controller) reconcileConfigMaps(grafana *aimsv1.Grafana, cms []v1.ConfigMap) error {
+	for i := range cms {
+		cm := &cms[i]
+		foundCM, err := c.kubeClientset.CoreV1().ConfigMaps(cm.Namespace).Get(cm.Name, metav1.GetOptions{})
+
+		if err != nil && errors.IsNotFound(err) {
+			_, err = c.kubeClientset.CoreV1().ConfigMaps(cm.Namespace).Create(cm)
+			if err != nil {
+				return err
+			}
+			klog.Infof("configmap created: %s", cm.Name)
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		// Only the data, the managed labels and the owner reference are ours, whatever else
+		// others set on the configmap is kept
+		updated := foundCM.DeepCopy()
+		adopted, err := claim(c.recorder, grafana, "ConfigMap", updated)
+		if err != nil {
+			return err
+		}
+		if adopted || !reflect.DeepEqual(foundCM.Data, cm.Data) || !util.HasLabels(grafana, foundCM.Labels) {
+			updated.Data = cm.Data
+			util.SetLabels(grafana, &updated.Labels)
+			_, err = c.kubeClientset.CoreV1().ConfigMaps(cm.Namespace).Update(updated)
+			if err != nil {
+				return err
+			}
+			klog.Infof("configmap updated: %s", cm.Name)
+		}
+	}
+	return nil
+}
+
+// adoptLegacyConfigMaps labels the fixed-name configmaps earlier versions created for the
+// instance, so that they join its inventory and are pruned like any other object it no longer
+// wants, honouring the prune annotation. Unowned ones are adopted through the adopt
+// annotation, those of other owners are left alone.
+func (c *Controller) adoptLegacyConfigMaps(grafana *aimsv1.Grafana) error {
+	for _, name := range util.LegacyConfigMapNames() {
+		found, err := c.configMapLister.ConfigMaps(grafana.Namespace).Get(name)
+		if err != nil && errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		cm := found.DeepCopy()
+		adopted, err := claim(c.recorder, grafana, "ConfigMap", cm)
+		if _, ok := err.(*conflictError); ok {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if !util.SetLabels(grafana, &cm.Labels) && !adopted {
+			continue
+		}
+		if _, err = c.kubeClientset.CoreV1().ConfigMaps(cm.Namespace).Update(cm); err != nil {
+			return err
+		}
+		klog.Infof("legacy configmap adopted: %s", cm.Name)
+	}
+	return nil
+}
+
+// orphanDeployment deletes a deployment whose selector has to change while keeping its pods
+// running. Its replicasets are labeled for the instance and orphaned, the replacement created
+// on the next reconcile removes them once it is available.
+func (c *Controller) orphanDeployment(grafana *aimsv1.Grafana, found *appsv1.Deployment) error {
+	if found.DeletionTimestamp != nil {
+		// Waiting for the garbage collector to orphan the replicasets
+		return nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(found.Spec.Selector)
+	if err != nil {
+		return err
+	}
+	replicaSets, err := c.kubeClientset.AppsV1().ReplicaSets(found.Namespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return err
+	}
+	for i := range replicaSets.Items {
+		rs := &replicaSets.Items[i]
+		if !metav1.IsControlledBy(rs, found) || rs.Labels[replacedByLabel] == grafana.Name {
+			continue
+		}
+		rs = rs.DeepCopy()
+		if rs.Labels == nil {
+			rs.Labels = map[string]string{}
+		}
+		rs.Labels[replacedByLabel] = grafana.Name
+		if _, err = c.kubeClientset.AppsV1().ReplicaSets(rs.Namespace).Update(rs); err != nil {
+			return err
+		}
+	}
+
+	propagation := metav1.DeletePropagationOrphan
+	err = c.kubeClientset.AppsV1().Deployments(found.Namespace).Delete(found.Name, &metav1.DeleteOptions{
+		PropagationPolicy: &propagation,
+		Preconditions:     &metav1.Preconditions{UID: &found.UID},
+	})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	klog.Infof("deployment %s orphaned its pods to change its selector", found.Name)
+	c.recorder.Eventf(grafana, v1.EventTypeNormal, "DeploymentReplaced", "deployment %s is replaced to select the pods of this instance only, its pods keep running until the replacement is available", found.Name)
+	return nil
+}
+
+// replacedReplicaSets returns the names of the replicasets orphaned by orphanDeployment
+func (c *Controller) replacedReplicaSets(grafana *aimsv1.Grafana) ([]string, error) {
+	selector := labels.SelectorFromSet(labels.Set{replacedByLabel: grafana.Name})
+	replicaSets, err := c.kubeClientset.AppsV1().ReplicaSets(grafana.Namespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, rs := range replicaSets.Items {
+		if metav1.GetControllerOf(&rs) == nil {
+			names = append(names, rs.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// removeReplacedReplicaSets deletes the replicasets a deployment replaced once it is
+// available, and drops their names from deploy. It reports whether deploy changed. With the
+// Recreate strategy they go right away, as the old pods hold volumes the new ones need.
+func (c *Controller) removeReplacedReplicaSets(grafana *aimsv1.Grafana, deploy *appsv1.Deployment) (bool, error) {
+	names := deploy.Annotations[replacedReplicaSetsAnnotation]
+	if names == "" || (deploy.Spec.Strategy.Type != appsv1.RecreateDeploymentStrategyType && !deploymentAvailable(deploy)) {
+		return false, nil
+	}
+
+	propagation := metav1.DeletePropagationBackground
+	for _, name := range strings.Split(names, ",") {
+		rs, err := c.kubeClientset.AppsV1().ReplicaSets(deploy.Namespace).Get(name, metav1.GetOptions{})
+		if err != nil && errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return false, err
+		}
+		if rs.Labels[replacedByLabel] != grafana.Name || metav1.GetControllerOf(rs) != nil {
+			continue
+		}
+		err = c.kubeClientset.AppsV1().ReplicaSets(rs.Namespace).Delete(rs.Name, &metav1.DeleteOptions{
+			PropagationPolicy: &propagation,
+			Preconditions:     &metav1.Preconditions{UID: &rs.UID},
+		})
+		if err != nil && !errors.IsNotFound(err) {
+			return false, err
+		}
+		klog.Infof("replaced replicaset deleted: %s", rs.Name)
+	}
+	delete(deploy.Annotations, replacedReplicaSetsAnnotation)
+	return true, nil
+}
+
+// deploymentAvailable reports whether every replica of the current pod template is available
+func deploymentAvailable(deploy *appsv1.Deployment) bool {
+	desired := int32(1)
+	if deploy.Spec.Replicas != nil {
+		desired = *deploy.Spec.Replicas
+	}
+	return deploy.Status.ObservedGeneration >= deploy.Generation &&
+		deploy.Status.UpdatedReplicas >= desired && deploy.Status.AvailableReplicas >= desired
+}
+
+// reconcileDeployment creates the deployment of the instance and patches the fields the
+// operator owns back when they drift, from spec changes or from edits to the deployment.
+// It reports whether the pods are rolled out, i.e. the pod template was created or changed.
+func (c *Controller) reconcileDeployment(grafana *aimsv1.Grafana, desired *appsv1.Deployment) (bool, error) {
+	found, err := c.deploymentLister.Deployments(desired.Namespace).Get(desired.Name)
+	if err != nil && errors.IsNotFound(err) {
+		replaced, err := c.replacedReplicaSets(grafana)
+		if err != nil {
+			return false, err
+		}
+		if len(replaced) > 0 {
+			desired = desired.DeepCopy()
+			if desired.Annotations == nil {
+				desired.Annotations = map[string]string{}
+			}
+			desired.Annotations[replacedReplicaSetsAnnotation] = strings.Join(replaced, ",")
+		}
+		_, err = c.kubeClientset.AppsV1().Deployments(desired.Namespace).Create(desired)
+		if err != nil {
+			return false, err
+		}
+		klog.Infof("deployment launched: %s", desired.Name)
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	owned := found.DeepCopy()
+	adopted, err := claim(c.recorder, grafana, "Deployment", owned)
+	if err != nil {
+		return false, err
+	}
+
+	// The selector is immutable, deployments of earlier versions selecting every grafana
+	// pod of the namespace are replaced without taking their pods down
+	if !equality.Semantic.DeepEqual(found.Spec.Selector, desired.Spec.Selector) {
+		return false, c.orphanDeployment(grafana, found)
+	}
+
+	cleaned, err := c.removeReplacedReplicaSets(grafana, owned)
+	if err != nil {
+		return false, err
+	}
+
+	autoscaled, err := c.autoscaled(found)
+	if err != nil {
+		return false, err
+	}
+
+	updated, drifted := util.MergeDeployment(owned, desired, !autoscaled)
+	if len(drifted) == 0 && !adopted && !cleaned {
+		return false, nil
+	}
+
+	// Replicas following a new spec.replicas, e.g. set through the scale subresource, are a
+	// scale rather than drift
+	scaled := false
+	if grafana.Generation != grafana.Status.ObservedGeneration {
+		for i, field := range drifted {
+			if field == "replicas" {
+				drifted = append(drifted[:i], drifted[i+1:]...)
+				scaled = true
+				break
+			}
+		}
+	}
+
+	original, err := json.Marshal(found)
+	if err != nil {
+		return false, err
+	}
+	modified, err := json.Marshal(updated)
+	if err != nil {
+		return false, err
+	}
+	patch, err := strategicpatch.CreateTwoWayMergePatch(original, modified, appsv1.Deployment{})
+	if err != nil {
+		return false, err
+	}
+
+	_, err = c.kubeClientset.AppsV1().Deployments(found.Namespace).Patch(found.Name, types.StrategicMergePatchType, patch)
+	if err != nil {
+		klog.Errorf("unable to reconcile deployment: %s", err)
+		return false, err
+	}
+	if scaled {
+		klog.Infof("deployment %s scaled to %d replicas", found.Name, *updated.Spec.Replicas)
+		c.recorder.Eventf(grafana, v1.EventTypeNormal, "Scaled", "deployment %s scaled to %d replicas", found.Name, *updated.Spec.Replicas)
+	}
+	if len(drifted) > 0 {
+		klog.Infof("deployment %s patched, drifted: %s", found.Name, strings.Join(drifted, ", "))
+		c.recorder.Eventf(grafana, v1.EventTypeNormal, "DeploymentDrift", "deployment %s reconciled, drifted fields: %s", found.Name, strings.Join(drifted, ", "))
+	}
+
+	// Only a change of the pod template starts a rollout
+	return !equality.Semantic.DeepEqual(found.Spec.Template, updated.Spec.Template), nil
+}
+
+// autoscaled reports whether a HorizontalPodAutoscaler manages the replicas of the deployment.
+// Autoscalers targeting the Grafana resource scale it through spec.replicas instead.
+func (c *Controller) autoscaled(deploy *appsv1.Deployment) (bool, error) {
+	hpas, err := c.hpaLister.HorizontalPodAutoscalers(deploy.Namespace).List(labels.Everything())
+	if err != nil {
+		return false, err
+	}
+	for _, hpa := range hpas {
+		target := hpa.Spec.ScaleTargetRef
+		if target.Kind == "Deployment" && target.Name == deploy.Name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// reconcileService creates the Service of the instance and repairs drift of its managed fields
+func (c *Controller) reconcileService(grafana *aimsv1.Grafana) error {
+	svc := util.Service(grafana)
+	found, err := c.serviceLister.Services(svc.Namespace).Get(svc.Name)
+	if err != nil && errors.IsNotFound(err) {
+		_, err = c.kubeClientset.CoreV1().Services(svc.Namespace).Create(svc)
+		if err != nil {
+			return err
+		}
+		klog.Infof("service created: %s", svc.Name)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	owned := found.DeepCopy()
+	adopted, err := claim(c.recorder, grafana, "Service", owned)
+	if err != nil {
+		return err
+	}
+	updated, changed := util.MergeService(owned, svc)
+	if !changed && !adopted {
+		return nil
+	}
+	_, err = c.kubeClientset.CoreV1().Services(updated.Namespace).Update(updated)
+	if err != nil {
+		return err
+	}
+	klog.Infof("service updated: %s", updated.Name)
+	return nil
+}
+
+// reconcileDataClaim creates the data claim of the instance and keeps its owner reference in
+// line with the retain policy. Claims are never deleted here: dropping spec.storage leaves
+// the claim to the garbage collector or, when retained, to the user.
+func (c *Controller) reconcileDataClaim(grafana *aimsv1.Grafana) error {
+	if !util.ManagesDataClaim(grafana) {
+		return nil
+	}
+
+	pvc := util.DataClaim(grafana)
+	found, err := c.kubeClientset.CoreV1().PersistentVolumeClaims(pvc.Namespace).Get(pvc.Name, metav1.GetOptions{})
+	if err != nil && errors.IsNotFound(err) {
+		_, err = c.kubeClientset.CoreV1().PersistentVolumeClaims(pvc.Namespace).Create(pvc)
+		if err != nil {
+			return err
+		}
+		klog.Infof("persistentvolumeclaim created: %s", pvc.Name)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	// Claims without a controller are retained claims taken back, not conflicts
+	owner := metav1.GetControllerOf(found)
+	if owner != nil && owner.UID != grafana.UID {
+		return &conflictError{kind: "PersistentVolumeClaim", name: found.Name, owner: owner}
+	}
+
+	updated := found.DeepCopy()
+	util.SetLabels(grafana, &updated.Labels)
+	if util.RetainsDataClaim(grafana) {
+		updated.OwnerReferences = nil
+		for _, ref := range found.OwnerReferences {
+			if ref.UID != grafana.UID {
+				updated.OwnerReferences = append(updated.OwnerReferences, ref)
+			}
+		}
+	} else if owner == nil {
+		updated.OwnerReferences = append(updated.OwnerReferences, pvc.OwnerReferences...)
+	}
+
+	// Claims can only grow, and only on storage classes allowing expansion
+	size := pvc.Spec.Resources.Requests[v1.ResourceStorage]
+	if current := found.Spec.Resources.Requests[v1.ResourceStorage]; size.Cmp(current) > 0 {
+		if updated.Spec.Resources.Requests == nil {
+			updated.Spec.Resources.Requests = v1.ResourceList{}
+		}
+		updated.Spec.Resources.Requests[v1.ResourceStorage] = size
+	}
+
+	if equality.Semantic.DeepEqual(found, updated) {
+		return nil
+	}
+	_, err = c.kubeClientset.CoreV1().PersistentVolumeClaims(updated.Namespace).Update(updated)
+	if err != nil {
+		return err
+	}
+	klog.Infof("persistentvolumeclaim updated: %s", updated.Name)
+	return nil
+}
+
+// dashboardFolders returns the folder layout of the GrafanaDashboards attached to the
+// instance, as recorded by the dashboard controller in the custom dashboards configmaps.
+func (c *Controller) dashboardFolders(grafana *aimsv1.Grafana) (util.DashboardFolders, error) {
+	cms, err := c.configMapLister.ConfigMaps(grafana.Namespace).List(util.Selector(grafana))
+	if err != nil {
+		return nil, err
+	}
+	return util.CustomDashboardFolders(util.CustomDashboardsConfigMaps(grafana, cms)), nil
+}
+
+// selectedDataSources returns the GrafanaDataSource resources selected by the instance and
+// records them in its status. Datasources whose name is already taken are skipped.
+func (c *Controller) selectedDataSources(grafana *aimsv1.Grafana) ([]*aimsv1.GrafanaDataSource, error) {
+	grafana.Status.DataSources = nil
+	if grafana.Spec.DatasourceSelector == nil {
+		return nil, nil
+	}
+
+	all, err := c.dataSourceLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Namespace+"/"+all[i].Name < all[j].Namespace+"/"+all[j].Name
+	})
+
+	names := map[string]bool{}
+	hasDefault := false
+	for _, ds := range grafana.Spec.Datasources {
+		names[ds.Name] = true
+		hasDefault = hasDefault || ds.IsDefault
+	}
+
+	selected := []*aimsv1.GrafanaDataSource{}
+	for _, ds := range all {
+		if !util.DataSourceSelected(grafana, ds) {
+			continue
+		}
+		key := ds.Namespace + "/" + ds.Name
+		if names[ds.Spec.Name] {
+			c.recorder.Eventf(grafana, v1.EventTypeWarning, "DataSourceConflict", "skipping datasource %s, the name %s is already provisioned", key, ds.Spec.Name)
+			continue
+		}
+		names[ds.Spec.Name] = true
+		// Grafana rejects the whole provisioning file when several datasources are the default
+		if ds.Spec.IsDefault && hasDefault {
+			c.recorder.Eventf(grafana, v1.EventTypeWarning, "DataSourceConflict", "provisioning datasource %s as not default, another datasource is already the default", key)
+			ds = ds.DeepCopy()
+			ds.Spec.IsDefault = false
+		}
+		hasDefault = hasDefault || ds.Spec.IsDefault
+		selected = append(selected, ds)
+		grafana.Status.DataSources = append(grafana.Status.DataSources, key)
+	}
+	return selected, nil
+}
+
+// reconcileDatasourceSecret resolves the secureJsonDataFrom references of the inline and
+// selected datasources and keeps the secret provisioning them up to date. It returns the
+// desired secret.
+func (c *Controller) reconcileDatasourceSecret(grafana *aimsv1.Grafana, dataSources []*aimsv1.GrafanaDataSource) (*v1.Secret, error) {
+	secure := util.SecureJSONData{}
+	for _, ds := range grafana.Spec.Datasources {
+		if err := c.resolveSecureJSONData(grafana, ds, secure); err != nil {
+			return nil, err
+		}
+	}
+
+	external := []aimsv1.GrafanaDatasource{}
+	for _, ds := range dataSources {
+		// Secrets are read from the instance namespace, never from the one of the datasource,
+		// so that selecting a datasource cannot copy secrets out of another namespace
+		if err := c.resolveSecureJSONData(grafana, ds.Spec.GrafanaDatasource, secure); err != nil {
+			return nil, err
+		}
+		external = append(external, ds.Spec.GrafanaDatasource)
+	}
+
+	desired := util.DatasourceSecret(grafana, external, secure)
+	found, err := c.secretLister.Secrets(desired.Namespace).Get(desired.Name)
+
+	if err != nil && errors.IsNotFound(err) {
+		_, err = c.kubeClientset.CoreV1().Secrets(desired.Namespace).Create(desired)
+		if err != nil {
+			return nil, err
+		}
+		klog.Infof("datasource secret created: %s", desired.Name)
+		return desired, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	updated := found.DeepCopy()
+	adopted, err := claim(c.recorder, grafana, "Secret", updated)
+	if err != nil {
+		return nil, err
+	}
+	if adopted || !reflect.DeepEqual(found.Data, desired.Data) || !util.HasLabels(grafana, found.Labels) {
+		updated.Data = desired.Data
+		util.SetLabels(grafana, &updated.Labels)
+		_, err = c.kubeClientset.CoreV1().Secrets(desired.Namespace).Update(updated)
+		if err != nil {
+			return nil, err
+		}
+		klog.Infof("datasource secret updated: %s", desired.Name)
+	}
+
+	return desired, nil
+}
+
+func (c *Controller) resolveSecureJSONData(grafana *aimsv1.Grafana, ds aimsv1.GrafanaDatasource, secure util.SecureJSONData) error {
+	namespace := grafana.Namespace
+	for key, source := range ds.SecureJSONDataFrom {
+		ref := source.SecretKeyRef
+		if ref == nil {
+			continue
+		}
+
+		secret, err := c.secretLister.Secrets(namespace).Get(ref.Name)
+		if err != nil {
+			c.recorder.Eventf(grafana, v1.EventTypeWarning, "DatasourceSecretError", "unable to read secret %s/%s for datasource %s: %s", namespace, ref.Name, ds.Name, err)
+			return err
+		}
+		value, ok := secret.Data[ref.Key]
+		if !ok {
+			c.recorder.Eventf(grafana, v1.EventTypeWarning, "DatasourceSecretError", "secret %s/%s for datasource %s has no key %s", namespace, ref.Name, ds.Name, ref.Key)
+			return fmt.Errorf("secret %s/%s has no key %s", namespace, ref.Name, ref.Key)
+		}
+
+		if secure[ds.Name] == nil {
+			secure[ds.Name] = map[string]string{}
+		}
+		secure[ds.Name][key] = string(value)
+	}
+	return nil
+}
+
+// credentialsHash validates the secrets holding the admin credentials and the database password
+// and returns a hash of the keys the pods read from them, or an empty string when neither is
+// used. Edits of other keys or of the secret metadata leave the hash alone.
+func (c *Controller) credentialsHash(grafana *aimsv1.Grafana) (string, error) {
+	credentials := map[string]map[string]string{}
+
+	if ref := util.CredentialsRef(grafana); ref != nil {
+		userKey, passwordKey := util.CredentialsKeys(ref)
+		if err := c.secretValues(grafana, credentials, ref.Name, userKey, passwordKey); err != nil {
+			return "", err
+		}
+	}
+
+	if db := grafana.Spec.Database; db != nil && db.PasswordSecretRef != nil {
+		if err := c.secretValues(grafana, credentials, db.PasswordSecretRef.Name, db.PasswordSecretRef.Key); err != nil {
+			return "", err
+		}
+	}
+
+	if len(credentials) == 0 {
+		return "", nil
+	}
+	return util.CredentialsHash(credentials), nil
+}
+
+// secretValues checks that a secret holds all keys and adds their values to credentials
+func (c *Controller) secretValues(grafana *aimsv1.Grafana, credentials map[string]map[string]string, name string, keys ...string) error {
+	secret, err := c.secretLister.Secrets(grafana.Namespace).Get(name)
+	if err != nil && errors.IsNotFound(err) {
+		// A secret created during this reconcile may not have reached the cache yet
+		secret, err = c.kubeClientset.CoreV1().Secrets(grafana.Namespace).Get(name, metav1.GetOptions{})
+	}
+	if err != nil {
+		c.recorder.Eventf(grafana, v1.EventTypeWarning, "CredentialsSecretError", "unable to read credentials secret %s: %s", name, err)
+		return err
+	}
+
+	if credentials[name] == nil {
+		credentials[name] = map[string]string{}
+	}
+	for _, key := range keys {
+		if len(secret.Data[key]) == 0 {
+			c.recorder.Eventf(grafana, v1.EventTypeWarning, "CredentialsSecretError", "credentials secret %s has no key %s", name, key)
+			return fmt.Errorf("credentials secret %s/%s has no key %s", grafana.Namespace, name, key)
+		}
+		credentials[name][key] = string(secret.Data[key])
+	}
+	return nil
+}
+
+// enqueueGrafana takes a Grafana resource and converts it into a namespace/name
+// string which is then put onto the work queue. This method should *not* be
+// passed resources of any type other than Grafana.
+func (c *Controller) enqueueGrafana(obj interface{}, a actionType) {
+	var key string
+	var err error
+	action = a
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	klog.Infof("enqueue grafana with action: %s", action)
+	c.workqueue.Add(key)
+}
+
+// enqueue a  deployment and checks that the owner reference points to an Grafana object. It then
+// enqueues this Grafana object.
+func (c *Controller) enqueueDeployment(obj interface{}, a actionType) {
+	var deploy *appsv1.Deployment
+	var ok bool
+	action = a
+
+	if deploy, ok = obj.(*appsv1.Deployment); !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("error decoding deployment, invalid type"))
+			return
+		}
+		deploy, ok = tombstone.Obj.(*appsv1.Deployment)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("error decoding deployment tombstone, invalid type"))
+			return
+		}
+		klog.V(4).Infof("Recovered deleted deployment '%s' from tombstone", deploy.GetName())
+	}
+	if ownerRef := metav1.GetControllerOf(deploy); ownerRef != nil {
+		if ownerRef.Kind != "Grafana" {
+			return
+		}
+
+		grafana, err := c.gLister.Grafanas(deploy.GetNamespace()).Get(ownerRef.Name)
+		if err != nil {
+			klog.V(4).Infof("ignoring orphaned deploy '%s' of Grafana '%s'", deploy.GetSelfLink(), ownerRef.Name)
+			return
+		}
+
+		klog.Infof("enqueuing Grafana %s/%s because of deployment change or periodic reconcilation", grafana.Namespace, grafana.Name)
+		c.enqueueGrafana(grafana, action)
+	}
+}
+
+// enqueueService takes a service and enqueues the Grafana object controlling it
+func (c *Controller) enqueueService(obj interface{}, a actionType) {
+	var svc *v1.Service
+	var ok bool
+	action = a
+
+	if svc, ok = obj.(*v1.Service); !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("error decoding service, invalid type"))
+			return
+		}
+		svc, ok = tombstone.Obj.(*v1.Service)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("error decoding service tombstone, invalid type"))
+			return
+		}
+		klog.V(4).Infof("Recovered deleted service '%s' from tombstone", svc.GetName())
+	}
+	if ownerRef := metav1.GetControllerOf(svc); ownerRef != nil {
+		if ownerRef.Kind != "Grafana" {
+			return
+		}
+
+		grafana, err := c.gLister.Grafanas(svc.GetNamespace()).Get(ownerRef.Name)
+		if err != nil {
+			klog.V(4).Infof("ignoring orphaned service '%s' of Grafana '%s'", svc.GetSelfLink(), ownerRef.Name)
+			return
+		}
+
+		klog.Infof("enqueuing Grafana %s/%s because of service change", grafana.Namespace, grafana.Name)
+		c.enqueueGrafana(grafana, action)
+	}
+}
+
+// enqueue an autoscaler and looks up the Grafana object owning the deployment it targets.
+// It then enqueues this Grafana object, whose replicas are left to the autoscaler.
+func (c *Controller) enqueueHPA(obj interface{}, a actionType) {
+	var hpa *autoscalingv1.HorizontalPodAutoscaler
+	var ok bool
+	action = a
+
+	if hpa, ok = obj.(*autoscalingv1.HorizontalPodAutoscaler); !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("error decoding horizontalpodautoscaler, invalid type"))
+			return
+		}
+		hpa, ok = tombstone.Obj.(*autoscalingv1.HorizontalPodAutoscaler)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("error decoding horizontalpodautoscaler tombstone, invalid type"))
+			return
+		}
+		klog.V(4).Infof("Recovered deleted horizontalpodautoscaler '%s' from tombstone", hpa.GetName())
+	}
+
+	target := hpa.Spec.ScaleTargetRef
+	if target.Kind != "Deployment" {
+		return
+	}
+	deploy, err := c.deploymentLister.Deployments(hpa.Namespace).Get(target.Name)
+	if err != nil {
+		return
+	}
+	if ownerRef := metav1.GetControllerOf(deploy); ownerRef != nil && ownerRef.Kind == "Grafana" {
+		grafana, err := c.gLister.Grafanas(hpa.Namespace).Get(ownerRef.Name)
+		if err != nil {
+			return
+		}
+
+		klog.Infof("enqueuing Grafana %s/%s because of horizontalpodautoscaler change", grafana.Namespace, grafana.Name)
+		c.enqueueGrafana(grafana, action)
+	}
+}
+
+// enqueue a  configmap and checks that the owner reference points to an Grafana object. It then
+// enqueues this Grafana object.
+func (c *Controller) enqueueConfigMap(obj interface{}, a actionType) {
+	var cm *v1.ConfigMap
+	var ok bool
+	action = a
+
+	if cm, ok = obj.(*v1.ConfigMap); !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("error decoding configmap, invalid type"))
+			return
+		}
+		cm, ok = tombstone.Obj.(*v1.ConfigMap)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("error decoding configmap tombstone, invalid type"))
+			return
+		}
+		klog.V(4).Infof("Recovered deleted configmap '%s' from tombstone", cm.GetName())
+	}
+	if ownerRef := metav1.GetControllerOf(cm); ownerRef != nil {
+		if ownerRef.Kind != "Grafana" {
+			return
+		}
+
+		grafana, err := c.gLister.Grafanas(cm.GetNamespace()).Get(ownerRef.Name)
+		if err != nil {
+			klog.V(4).Infof("ignoring orphaned configmap '%s' of Grafana '%s'", cm.GetSelfLink(), ownerRef.Name)
+			return
+		}
+
+		klog.Infof("enqueuing Grafana %s/%s because of configmap change or periodic reconcilation", grafana.Namespace, grafana.Name)
+		c.enqueueGrafana(grafana, action)
+	}
+}
+
+// enqueue a secret and checks whether it is owned or referenced by Grafana objects.
+// It then enqueues those Grafana objects.
+func (c *Controller) enqueueSecret(obj interface{}, a actionType) {
+	var secret *v1.Secret
+	var ok bool
+	action = a
+
+	if secret, ok = obj.(*v1.Secret); !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("error decoding secret, invalid type"))
+			return
+		}
+		secret, ok = tombstone.Obj.(*v1.Secret)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("error decoding secret tombstone, invalid type"))
+			return
+		}
+		klog.V(4).Infof("Recovered deleted secret '%s' from tombstone", secret.GetName())
+	}
+
+	if ownerRef := metav1.GetControllerOf(secret); ownerRef != nil && ownerRef.Kind == "Grafana" {
+		grafana, err := c.gLister.Grafanas(secret.GetNamespace()).Get(ownerRef.Name)
+		if err != nil {
+			klog.V(4).Infof("ignoring orphaned secret '%s' of Grafana '%s'", secret.GetSelfLink(), ownerRef.Name)
+			return
+		}
+
+		klog.Infof("enqueuing Grafana %s/%s because of owned secret change", grafana.Namespace, grafana.Name)
+		c.enqueueGrafana(grafana, action)
+		return
+	}
+
+	grafanas, err := c.gLister.Grafanas(secret.GetNamespace()).List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+
+	// Selected datasources read their secrets from the namespace of the instance too
+	dataSources, err := c.dataSourceLister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+
+	for _, grafana := range grafanas {
+		if !util.ReferencesSecret(grafana, secret.GetName()) && !selectsSecret(grafana, dataSources, secret.GetName()) {
+			continue
+		}
+
+		klog.Infof("enqueuing Grafana %s/%s because of secret change", grafana.Namespace, grafana.Name)
+		c.enqueueGrafana(grafana, action)
+	}
+}
+
+// selectsSecret reports whether grafana selects one of dataSources referencing the secret name
+func selectsSecret(grafana *aimsv1.Grafana, dataSources []*aimsv1.GrafanaDataSource, name string) bool {
+	for _, ds := range dataSources {
+		if util.DatasourceReferencesSecret(ds.Spec.GrafanaDatasource, name) && util.DataSourceSelected(grafana, ds) {
+			return true
+		}
+	}
+	return false
+}
+
+// enqueue a datasource and looks up the Grafana objects selecting it.
+// It then enqueues those Grafana objects.
+func (c *Controller) enqueueDataSource(obj interface{}, a actionType) {
+	var ds *aimsv1.GrafanaDataSource
+	var ok bool
+	action = a
+
+	if ds, ok = obj.(*aimsv1.GrafanaDataSource); !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("error decoding datasource, invalid type"))
+			return
+		}
+		ds, ok = tombstone.Obj.(*aimsv1.GrafanaDataSource)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("error decoding datasource tombstone, invalid type"))
+			return
+		}
+		klog.V(4).Infof("Recovered deleted datasource '%s' from tombstone", ds.GetName())
+	}
+
+	grafanas, err := c.gLister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+
+	for _, grafana := range grafanas {
+		if !util.DataSourceSelected(grafana, ds) {
+			continue
+		}
+
+		klog.Infof("enqueuing Grafana %s/%s because of datasource change", grafana.Namespace, grafana.Name)
 		c.enqueueGrafana(grafana, action)
 	}
 }
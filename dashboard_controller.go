@@ -0,0 +1,380 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	corev1informer "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1lister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+	clientset "github.com/dichque/grafana-operator/pkg/client/clientset/versioned"
+	"github.com/dichque/grafana-operator/pkg/client/clientset/versioned/scheme"
+	ginformers "github.com/dichque/grafana-operator/pkg/client/informers/externalversions/grafana/v1"
+	glisters "github.com/dichque/grafana-operator/pkg/client/listers/grafana/v1"
+	"github.com/dichque/grafana-operator/pkg/util"
+)
+
+const dashboardControllerName string = "grafana-dashboard-controller"
+
+// DashboardController attaches GrafanaDashboard resources to the Grafana resources
+// they select by writing them into the custom dashboards configmap of each instance.
+type DashboardController struct {
+	kubeClientset    kubernetes.Interface
+	grafanaClientset clientset.Interface
+
+	gLister glisters.GrafanaLister
+	gSynced cache.InformerSynced
+
+	dashboardLister glisters.GrafanaDashboardLister
+	dashboardSynced cache.InformerSynced
+
+	folderLister glisters.GrafanaFolderLister
+	folderSynced cache.InformerSynced
+
+	configMapLister corev1lister.ConfigMapLister
+	configMapSynced cache.InformerSynced
+
+	workqueue workqueue.RateLimitingInterface
+	recorder  record.EventRecorder
+}
+
+// NewDashboardController implementation for GrafanaDashboard resources
+func NewDashboardController(
+	kubeClientset kubernetes.Interface,
+	grafanaClientset clientset.Interface,
+	ginformer ginformers.GrafanaInformer,
+	dashboardInformer ginformers.GrafanaDashboardInformer,
+	folderInformer ginformers.GrafanaFolderInformer,
+	configMapInformer corev1informer.ConfigMapInformer) *DashboardController {
+
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(klog.Infof)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClientset.CoreV1().Events("")})
+	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: dashboardControllerName})
+
+	controller := &DashboardController{
+		kubeClientset:    kubeClientset,
+		grafanaClientset: grafanaClientset,
+		gLister:          ginformer.Lister(),
+		gSynced:          ginformer.Informer().HasSynced,
+		dashboardLister:  dashboardInformer.Lister(),
+		dashboardSynced:  dashboardInformer.Informer().HasSynced,
+		folderLister:     folderInformer.Lister(),
+		folderSynced:     folderInformer.Informer().HasSynced,
+		configMapLister:  configMapInformer.Lister(),
+		configMapSynced:  configMapInformer.Informer().HasSynced,
+		workqueue:        workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "GrafanaDashboard"),
+		recorder:         recorder,
+	}
+
+	klog.Info("Setting up dashboard event handlers")
+	dashboardInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueDashboard,
+		UpdateFunc: func(old, new interface{}) {
+			controller.enqueueDashboard(new)
+		},
+		DeleteFunc: controller.enqueueDashboard,
+	})
+
+	// Grafana label changes can attach or detach dashboards of the namespace
+	ginformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueNamespaceDashboards,
+		UpdateFunc: func(old, new interface{}) {
+			controller.enqueueNamespaceDashboards(new)
+		},
+	})
+
+	// Folder title or uid changes move the dashboards placed into the folder
+	folderInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueFolderDashboards,
+		UpdateFunc: func(old, new interface{}) {
+			controller.enqueueFolderDashboards(new)
+		},
+		DeleteFunc: controller.enqueueFolderDashboards,
+	})
+	return controller
+}
+
+// Run starts the dashboard workers and blocks until stopCh is closed
+func (c *DashboardController) Run(threadiness int, stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+	defer c.workqueue.ShutDown()
+
+	klog.Info("Starting grafana dashboard controller")
+
+	klog.Info("Waiting for dashboard informer caches to sync")
+	if ok := cache.WaitForCacheSync(stopCh, c.gSynced, c.dashboardSynced, c.folderSynced, c.configMapSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	for i := 0; i < threadiness; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+
+	klog.Info("Started dashboard workers")
+	<-stopCh
+	klog.Info("Shutting down dashboard workers")
+
+	return nil
+}
+
+func (c *DashboardController) runWorker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *DashboardController) processNextWorkItem() bool {
+	obj, shutdown := c.workqueue.Get()
+
+	if shutdown {
+		return false
+	}
+
+	defer c.workqueue.Done(obj)
+
+	key, ok := obj.(string)
+	if !ok {
+		c.workqueue.Forget(obj)
+		utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
+		return true
+	}
+
+	err := c.reconcile(key)
+
+	if err == nil {
+		c.workqueue.Forget(key)
+	} else if c.workqueue.NumRequeues(key) < maxRetries {
+		c.workqueue.AddRateLimited(key)
+		klog.Info("Re-processing the dashboard queue")
+	} else {
+		c.workqueue.Forget(key)
+		klog.Error("Max retries reached")
+	}
+
+	if err != nil {
+		utilruntime.HandleError(err)
+	}
+
+	return true
+}
+
+func (c *DashboardController) reconcile(key string) error {
+	klog.Infof("=== Reconciling GrafanaDashboard %s", key)
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	grafanas, err := c.gLister.Grafanas(namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	original, err := c.dashboardLister.GrafanaDashboards(namespace).Get(name)
+	if err != nil && errors.IsNotFound(err) {
+		// Detach the deleted dashboard from every instance of the namespace
+		for _, grafana := range grafanas {
+			if err = c.detach(grafana, util.DashboardKey(name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	dashboard := original.DeepCopy()
+	dashboard.Status.Message = ""
+
+	valid := json.Valid([]byte(dashboard.Spec.JSON))
+	if !valid {
+		dashboard.Status.Message = "spec.json is not a valid dashboard model"
+		c.recorder.Event(dashboard, v1.EventTypeWarning, "InvalidDashboard", dashboard.Status.Message)
+	}
+
+	folder, err := c.folderOf(dashboard)
+	if err != nil {
+		return err
+	}
+
+	instances := []string{}
+	for _, grafana := range grafanas {
+		if valid && util.DashboardMatches(dashboard, grafana) {
+			if err = c.attach(grafana, dashboard, folder); err != nil {
+				return err
+			}
+			instances = append(instances, grafana.Name)
+		} else if err = c.detach(grafana, util.DashboardKey(dashboard.Name)); err != nil {
+			return err
+		}
+	}
+	sort.Strings(instances)
+	dashboard.Status.Instances = instances
+
+	if !reflect.DeepEqual(original.Status, dashboard.Status) {
+		_, err = c.grafanaClientset.AimsV1().GrafanaDashboards(namespace).UpdateStatus(dashboard)
+		if err != nil {
+			klog.Errorf("Unable to update status of grafana dashboard: %s : %s", dashboard.Name, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// folderOf resolves the folder named by spec.folder of the dashboard
+func (c *DashboardController) folderOf(dashboard *aimsv1.GrafanaDashboard) (util.DashboardFolder, error) {
+	if dashboard.Spec.Folder == "" {
+		return util.DashboardFolder{}, nil
+	}
+
+	folder, err := c.folderLister.GrafanaFolders(dashboard.Namespace).Get(dashboard.Spec.Folder)
+	if err != nil && errors.IsNotFound(err) {
+		return util.FolderOf(dashboard, nil), nil
+	} else if err != nil {
+		return util.DashboardFolder{}, err
+	}
+	return util.FolderOf(dashboard, folder), nil
+}
+
+// attach writes the dashboard into the custom dashboards configmap of its folder, and drops
+// it from the ones of the other folders of the instance
+func (c *DashboardController) attach(grafana *aimsv1.Grafana, dashboard *aimsv1.GrafanaDashboard, folder util.DashboardFolder) error {
+	key := util.DashboardKey(dashboard.Name)
+	name := util.CustomDashboardsFolderName(grafana, folder)
+	found, err := c.configMapLister.ConfigMaps(grafana.Namespace).Get(name)
+	if err != nil && errors.IsNotFound(err) {
+		cm := util.CustomDashboardsConfigMap(grafana, folder)
+		util.SetCustomDashboard(cm, key, dashboard.Spec.JSON, folder)
+		_, err = c.kubeClientset.CoreV1().ConfigMaps(cm.Namespace).Create(cm)
+		if err != nil {
+			return err
+		}
+		klog.Infof("dashboard %s attached to grafana %s/%s", dashboard.Name, grafana.Namespace, grafana.Name)
+		return c.removeDashboard(grafana, key, name)
+	} else if err != nil {
+		return err
+	}
+
+	cm := found.DeepCopy()
+	adopted, err := claim(c.recorder, grafana, "ConfigMap", cm)
+	if err != nil {
+		return err
+	}
+	changed := util.SetCustomDashboard(cm, key, dashboard.Spec.JSON, folder)
+	if util.SetLabels(grafana, &cm.Labels) || changed || adopted {
+		_, err = c.kubeClientset.CoreV1().ConfigMaps(cm.Namespace).Update(cm)
+		if err != nil {
+			return err
+		}
+		klog.Infof("dashboard %s attached to grafana %s/%s", dashboard.Name, grafana.Namespace, grafana.Name)
+	}
+	return c.removeDashboard(grafana, key, name)
+}
+
+// detach removes a dashboard file from the custom dashboards configmaps of the instance
+func (c *DashboardController) detach(grafana *aimsv1.Grafana, key string) error {
+	return c.removeDashboard(grafana, key, "")
+}
+
+// removeDashboard removes a dashboard file from the custom dashboards configmaps of the
+// instance but the one named keep. Configmaps of folders left empty are deleted, so that
+// their directory and provider go with the next rollout.
+func (c *DashboardController) removeDashboard(grafana *aimsv1.Grafana, key, keep string) error {
+	cms, err := c.configMapLister.ConfigMaps(grafana.Namespace).List(util.Selector(grafana))
+	if err != nil {
+		return err
+	}
+
+	for _, found := range util.CustomDashboardsConfigMaps(grafana, cms) {
+		if found.Name == keep || !metav1.IsControlledBy(found, grafana) {
+			continue
+		}
+		cm := found.DeepCopy()
+		if !util.RemoveCustomDashboard(cm, key) {
+			continue
+		}
+
+		if len(cm.Data) == 0 && cm.Name != util.CustomDashboardsName(grafana) {
+			err = c.kubeClientset.CoreV1().ConfigMaps(cm.Namespace).Delete(cm.Name, &metav1.DeleteOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		} else if _, err = c.kubeClientset.CoreV1().ConfigMaps(cm.Namespace).Update(cm); err != nil {
+			return err
+		}
+		klog.Infof("dashboard %s detached from grafana %s/%s", key, grafana.Namespace, grafana.Name)
+	}
+	return nil
+}
+
+// enqueueDashboard takes a GrafanaDashboard resource and puts its namespace/name key
+// onto the work queue.
+func (c *DashboardController) enqueueDashboard(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.workqueue.Add(key)
+}
+
+// enqueueNamespaceDashboards enqueues every GrafanaDashboard in the namespace of a Grafana resource
+func (c *DashboardController) enqueueNamespaceDashboards(obj interface{}) {
+	grafana, ok := obj.(*aimsv1.Grafana)
+	if !ok {
+		utilruntime.HandleError(fmt.Errorf("error decoding grafana, invalid type"))
+		return
+	}
+
+	dashboards, err := c.dashboardLister.GrafanaDashboards(grafana.Namespace).List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, dashboard := range dashboards {
+		c.enqueueDashboard(dashboard)
+	}
+}
+
+// enqueueFolderDashboards enqueues the GrafanaDashboards of the namespace placed into a GrafanaFolder
+func (c *DashboardController) enqueueFolderDashboards(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	folder, ok := obj.(*aimsv1.GrafanaFolder)
+	if !ok {
+		utilruntime.HandleError(fmt.Errorf("error decoding grafana folder, invalid type"))
+		return
+	}
+
+	dashboards, err := c.dashboardLister.GrafanaDashboards(folder.Namespace).List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, dashboard := range dashboards {
+		if dashboard.Spec.Folder == folder.Name {
+			c.enqueueDashboard(dashboard)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+	clientset "github.com/dichque/grafana-operator/pkg/client/clientset/versioned"
+	"github.com/dichque/grafana-operator/pkg/client/clientset/versioned/scheme"
+	ginformers "github.com/dichque/grafana-operator/pkg/client/informers/externalversions/grafana/v1"
+	glisters "github.com/dichque/grafana-operator/pkg/client/listers/grafana/v1"
+	"github.com/dichque/grafana-operator/pkg/util"
+)
+
+const dataSourceControllerName string = "grafana-datasource-controller"
+
+// DataSourceController reports in the status of every GrafanaDataSource whether the
+// Grafana resources selecting it have provisioned it.
+type DataSourceController struct {
+	kubeClientset    kubernetes.Interface
+	grafanaClientset clientset.Interface
+
+	gLister glisters.GrafanaLister
+	gSynced cache.InformerSynced
+
+	dataSourceLister glisters.GrafanaDataSourceLister
+	dataSourceSynced cache.InformerSynced
+
+	workqueue workqueue.RateLimitingInterface
+	recorder  record.EventRecorder
+}
+
+// NewDataSourceController implementation for GrafanaDataSource resources
+func NewDataSourceController(
+	kubeClientset kubernetes.Interface,
+	grafanaClientset clientset.Interface,
+	ginformer ginformers.GrafanaInformer,
+	dataSourceInformer ginformers.GrafanaDataSourceInformer) *DataSourceController {
+
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(klog.Infof)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClientset.CoreV1().Events("")})
+	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: dataSourceControllerName})
+
+	controller := &DataSourceController{
+		kubeClientset:    kubeClientset,
+		grafanaClientset: grafanaClientset,
+		gLister:          ginformer.Lister(),
+		gSynced:          ginformer.Informer().HasSynced,
+		dataSourceLister: dataSourceInformer.Lister(),
+		dataSourceSynced: dataSourceInformer.Informer().HasSynced,
+		workqueue:        workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "GrafanaDataSource"),
+		recorder:         recorder,
+	}
+
+	klog.Info("Setting up datasource event handlers")
+	dataSourceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueDataSource,
+		UpdateFunc: func(old, new interface{}) {
+			controller.enqueueDataSource(new)
+		},
+	})
+
+	// Grafana status and selector changes alter the sync state of the datasources
+	ginformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueAllDataSources,
+		UpdateFunc: func(old, new interface{}) {
+			controller.enqueueAllDataSources(new)
+		},
+		DeleteFunc: controller.enqueueAllDataSources,
+	})
+	return controller
+}
+
+// Run starts the datasource workers and blocks until stopCh is closed
+func (c *DataSourceController) Run(threadiness int, stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+	defer c.workqueue.ShutDown()
+
+	klog.Info("Starting grafana datasource controller")
+
+	klog.Info("Waiting for datasource informer caches to sync")
+	if ok := cache.WaitForCacheSync(stopCh, c.gSynced, c.dataSourceSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	for i := 0; i < threadiness; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+
+	klog.Info("Started datasource workers")
+	<-stopCh
+	klog.Info("Shutting down datasource workers")
+
+	return nil
+}
+
+func (c *DataSourceController) runWorker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *DataSourceController) processNextWorkItem() bool {
+	obj, shutdown := c.workqueue.Get()
+
+	if shutdown {
+		return false
+	}
+
+	defer c.workqueue.Done(obj)
+
+	key, ok := obj.(string)
+	if !ok {
+		c.workqueue.Forget(obj)
+		utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
+		return true
+	}
+
+	err := c.reconcile(key)
+
+	if err == nil {
+		c.workqueue.Forget(key)
+	} else if c.workqueue.NumRequeues(key) < maxRetries {
+		c.workqueue.AddRateLimited(key)
+		klog.Info("Re-processing the datasource queue")
+	} else {
+		c.workqueue.Forget(key)
+		klog.Error("Max retries reached")
+	}
+
+	if err != nil {
+		utilruntime.HandleError(err)
+	}
+
+	return true
+}
+
+func (c *DataSourceController) reconcile(key string) error {
+	klog.Infof("=== Reconciling GrafanaDataSource %s", key)
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	original, err := c.dataSourceLister.GrafanaDataSources(namespace).Get(name)
+	if err != nil && errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	grafanas, err := c.gLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	ds := original.DeepCopy()
+	ds.Status.Instances = nil
+	for _, grafana := range grafanas {
+		if !util.DataSourceSelected(grafana, ds) {
+			continue
+		}
+
+		instance := aimsv1.GrafanaDataSourceInstance{
+			Grafana: grafana.Namespace + "/" + grafana.Name,
+			Synced:  provisions(grafana, key),
+		}
+		if !instance.Synced {
+			instance.Message = "not provisioned, see the events of the Grafana resource"
+		}
+		ds.Status.Instances = append(ds.Status.Instances, instance)
+	}
+
+	if !reflect.DeepEqual(original.Status, ds.Status) {
+		_, err = c.grafanaClientset.AimsV1().GrafanaDataSources(namespace).UpdateStatus(ds)
+		if err != nil {
+			klog.Errorf("Unable to update status of grafana datasource: %s : %s", ds.Name, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// provisions reports whether the instance status lists the GrafanaDataSource key
+func provisions(grafana *aimsv1.Grafana, key string) bool {
+	for _, provisioned := range grafana.Status.DataSources {
+		if provisioned == key {
+			return true
+		}
+	}
+	return false
+}
+
+// enqueueDataSource takes a GrafanaDataSource resource and puts its namespace/name key
+// onto the work queue.
+func (c *DataSourceController) enqueueDataSource(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.workqueue.Add(key)
+}
+
+// enqueueAllDataSources enqueues every GrafanaDataSource as any of them may be selected by a Grafana resource
+func (c *DataSourceController) enqueueAllDataSources(obj interface{}) {
+	dataSources, err := c.dataSourceLister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, ds := range dataSources {
+		c.enqueueDataSource(ds)
+	}
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	corev1informer "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1lister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+	clientset "github.com/dichque/grafana-operator/pkg/client/clientset/versioned"
+	"github.com/dichque/grafana-operator/pkg/client/clientset/versioned/scheme"
+	ginformers "github.com/dichque/grafana-operator/pkg/client/informers/externalversions/grafana/v1"
+	glisters "github.com/dichque/grafana-operator/pkg/client/listers/grafana/v1"
+	"github.com/dichque/grafana-operator/pkg/grafanaapi"
+	"github.com/dichque/grafana-operator/pkg/util"
+)
+
+const folderControllerName string = "grafana-folder-controller"
+
+// folderPermissionLevels maps folder permission types to the levels of the grafana API
+var folderPermissionLevels = map[aimsv1.FolderPermissionType]int{
+	aimsv1.FolderPermissionView:  1,
+	aimsv1.FolderPermissionEdit:  2,
+	aimsv1.FolderPermissionAdmin: 4,
+}
+
+// FolderController creates GrafanaFolder resources through the HTTP API of the
+// Grafana resources they select and applies their permissions.
+type FolderController struct {
+	kubeClientset    kubernetes.Interface
+	grafanaClientset clientset.Interface
+
+	gLister glisters.GrafanaLister
+	gSynced cache.InformerSynced
+
+	folderLister glisters.GrafanaFolderLister
+	folderSynced cache.InformerSynced
+
+	secretLister corev1lister.SecretLister
+	secretSynced cache.InformerSynced
+
+	workqueue workqueue.RateLimitingInterface
+	recorder  record.EventRecorder
+}
+
+// NewFolderController implementation for GrafanaFolder resources
+func NewFolderController(
+	kubeClientset kubernetes.Interface,
+	grafanaClientset clientset.Interface,
+	ginformer ginformers.GrafanaInformer,
+	folderInformer ginformers.GrafanaFolderInformer,
+	secretInformer corev1informer.SecretInformer) *FolderController {
+
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(klog.Infof)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClientset.CoreV1().Events("")})
+	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: folderControllerName})
+
+	controller := &FolderController{
+		kubeClientset:    kubeClientset,
+		grafanaClientset: grafanaClientset,
+		gLister:          ginformer.Lister(),
+		gSynced:          ginformer.Informer().HasSynced,
+		folderLister:     folderInformer.Lister(),
+		folderSynced:     folderInformer.Informer().HasSynced,
+		secretLister:     secretInformer.Lister(),
+		secretSynced:     secretInformer.Informer().HasSynced,
+		workqueue:        workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "GrafanaFolder"),
+		recorder:         recorder,
+	}
+
+	klog.Info("Setting up folder event handlers")
+	folderInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueFolder,
+		UpdateFunc: func(old, new interface{}) {
+			controller.enqueueFolder(new)
+		},
+	})
+
+	// New or relabeled instances, and pods becoming ready, need the folders of the namespace
+	ginformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueNamespaceFolders,
+		UpdateFunc: func(old, new interface{}) {
+			controller.enqueueNamespaceFolders(new)
+		},
+	})
+	return controller
+}
+
+// Run starts the folder workers and blocks until stopCh is closed
+func (c *FolderController) Run(threadiness int, stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+	defer c.workqueue.ShutDown()
+
+	klog.Info("Starting grafana folder controller")
+
+	klog.Info("Waiting for folder informer caches to sync")
+	if ok := cache.WaitForCacheSync(stopCh, c.gSynced, c.folderSynced, c.secretSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	for i := 0; i < threadiness; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+
+	klog.Info("Started folder workers")
+	<-stopCh
+	klog.Info("Shutting down folder workers")
+
+	return nil
+}
+
+func (c *FolderController) runWorker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *FolderController) processNextWorkItem() bool {
+	obj, shutdown := c.workqueue.Get()
+
+	if shutdown {
+		return false
+	}
+
+	defer c.workqueue.Done(obj)
+
+	key, ok := obj.(string)
+	if !ok {
+		c.workqueue.Forget(obj)
+		utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
+		return true
+	}
+
+	err := c.reconcile(key)
+
+	if err == nil {
+		c.workqueue.Forget(key)
+	} else if c.workqueue.NumRequeues(key) < maxRetries {
+		c.workqueue.AddRateLimited(key)
+		klog.Info("Re-processing the folder queue")
+	} else {
+		c.workqueue.Forget(key)
+		klog.Error("Max retries reached")
+	}
+
+	if err != nil {
+		utilruntime.HandleError(err)
+	}
+
+	return true
+}
+
+// reconcile creates the folder in every selected instance. Folders are left in grafana
+// when the GrafanaFolder is deleted as they may hold dashboards created in the UI.
+func (c *FolderController) reconcile(key string) error {
+	klog.Infof("=== Reconciling GrafanaFolder %s", key)
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	original, err := c.folderLister.GrafanaFolders(namespace).Get(name)
+	if err != nil && errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	grafanas, err := c.gLister.Grafanas(namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	folder := original.DeepCopy()
+	folder.Status.UID = util.FolderUID(folder)
+	folder.Status.Message = ""
+
+	var syncErr error
+	instances := []string{}
+	for _, grafana := range grafanas {
+		if !util.FolderMatches(folder, grafana) {
+			continue
+		}
+		if err = c.syncFolder(grafana, folder); err != nil {
+			syncErr = fmt.Errorf("unable to sync folder to grafana %s: %s", grafana.Name, err)
+			folder.Status.Message = syncErr.Error()
+			c.recorder.Event(folder, v1.EventTypeWarning, "FolderSyncFailed", syncErr.Error())
+			continue
+		}
+		instances = append(instances, grafana.Name)
+	}
+	sort.Strings(instances)
+	folder.Status.Instances = instances
+
+	if !reflect.DeepEqual(original.Status, folder.Status) {
+		_, err = c.grafanaClientset.AimsV1().GrafanaFolders(namespace).UpdateStatus(folder)
+		if err != nil {
+			klog.Errorf("Unable to update status of grafana folder: %s : %s", folder.Name, err)
+			return err
+		}
+	}
+
+	return syncErr
+}
+
+// syncFolder creates or renames the folder and applies its permissions on every ready pod
+func (c *FolderController) syncFolder(grafana *aimsv1.Grafana, folder *aimsv1.GrafanaFolder) error {
+	clients, err := adminClients(c.kubeClientset, c.secretLister, grafana)
+	if err != nil {
+		return err
+	}
+
+	uid := util.FolderUID(folder)
+	for _, client := range clients {
+		found, err := client.GetFolder(uid)
+		if err != nil && grafanaapi.IsNotFound(err) {
+			if _, err = client.CreateFolder(uid, folder.Spec.Title); err != nil {
+				return err
+			}
+			klog.Infof("folder %s created in grafana %s/%s", uid, grafana.Namespace, grafana.Name)
+		} else if err != nil {
+			return err
+		} else if found.Title != folder.Spec.Title {
+			if err = client.UpdateFolderTitle(uid, folder.Spec.Title); err != nil {
+				return err
+			}
+		}
+
+		// Permissions managed in the UI are kept unless the spec lists some
+		if folder.Spec.Permissions == nil {
+			continue
+		}
+		items, err := permissionItems(client, folder.Spec.Permissions)
+		if err != nil {
+			return err
+		}
+		if err = client.SetFolderPermissions(uid, items); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// permissionItems resolves the teams and users of the folder permissions to grafana ids
+func permissionItems(client *grafanaapi.Client, permissions []aimsv1.FolderPermission) ([]grafanaapi.PermissionItem, error) {
+	items := []grafanaapi.PermissionItem{}
+	for _, permission := range permissions {
+		level, ok := folderPermissionLevels[permission.Permission]
+		if !ok {
+			return nil, fmt.Errorf("unknown folder permission %q", permission.Permission)
+		}
+
+		item := grafanaapi.PermissionItem{Permission: level}
+		switch {
+		case permission.Team != "":
+			team, err := client.LookupTeam(permission.Team)
+			if err != nil {
+				return nil, err
+			}
+			item.TeamID = team.ID
+		case permission.User != "":
+			user, err := client.LookupUser(permission.User)
+			if err != nil {
+				return nil, err
+			}
+			item.UserID = user.ID
+		case permission.Role != "":
+			item.Role = permission.Role
+		default:
+			return nil, fmt.Errorf("folder permission needs one of team, user or role")
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// enqueueFolder takes a GrafanaFolder resource and puts its namespace/name key
+// onto the work queue.
+func (c *FolderController) enqueueFolder(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.workqueue.Add(key)
+}
+
+// enqueueNamespaceFolders enqueues every GrafanaFolder in the namespace of a Grafana resource
+func (c *FolderController) enqueueNamespaceFolders(obj interface{}) {
+	grafana, ok := obj.(*aimsv1.Grafana)
+	if !ok {
+		utilruntime.HandleError(fmt.Errorf("error decoding grafana, invalid type"))
+		return
+	}
+
+	folders, err := c.folderLister.GrafanaFolders(grafana.Namespace).List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, folder := range folders {
+		c.enqueueFolder(folder)
+	}
+}
@@ -3,8 +3,10 @@ module github.com/dichque/grafana-operator
 go 1.14
 
 require (
+	github.com/google/gofuzz v1.0.0
 	k8s.io/api v0.17.0
 	k8s.io/apimachinery v0.17.0
 	k8s.io/client-go v0.17.0
 	k8s.io/klog v1.0.0
+	sigs.k8s.io/yaml v1.1.0
 )
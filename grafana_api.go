@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	corev1lister "k8s.io/client-go/listers/core/v1"
+
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+	"github.com/dichque/grafana-operator/pkg/grafanaapi"
+	"github.com/dichque/grafana-operator/pkg/util"
+)
+
+// grafanaEndpoints returns the API urls of the ready pods of a grafana instance
+func grafanaEndpoints(kubeClientset kubernetes.Interface, grafana *aimsv1.Grafana) ([]string, error) {
+	deploy := util.Deployment(grafana, nil)
+	selector := labels.SelectorFromSet(deploy.Spec.Selector.MatchLabels)
+	pods, err := kubeClientset.CoreV1().Pods(grafana.Namespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := []string{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !podOwnedBy(pod, deploy) || !podReady(pod) {
+			continue
+		}
+		endpoints = append(endpoints, fmt.Sprintf("http://%s:%d", pod.Status.PodIP, util.GrafanaPort))
+	}
+	return endpoints, nil
+}
+
+// adminEndpoints returns the endpoints the admin password of an instance is changed through.
+// Replicas on an external database share its user table, so the password is changed once
+// through the Service. Pods with their own sqlite database are changed one by one.
+func adminEndpoints(kubeClientset kubernetes.Interface, grafana *aimsv1.Grafana) ([]string, error) {
+	endpoints, err := grafanaEndpoints(kubeClientset, grafana)
+	if err != nil || len(endpoints) == 0 || grafana.Spec.Database == nil {
+		return endpoints, err
+	}
+	svc := util.Service(grafana)
+	return []string{fmt.Sprintf("http://%s.%s.svc:%d", svc.Name, svc.Namespace, svc.Spec.Ports[0].Port)}, nil
+}
+
+func setAdminPassword(endpoint, user, current, password string) error {
+	client := grafanaapi.NewClient(endpoint, user, current)
+	admin, err := client.LookupUser(user)
+	if err != nil {
+		return err
+	}
+	return client.UpdateUserPassword(admin.ID, password)
+}
+
+// podOwnedBy reports whether the pod belongs to a replicaset of the deployment
+func podOwnedBy(pod *v1.Pod, deploy *appsv1.Deployment) bool {
+	ownerRef := metav1.GetControllerOf(pod)
+	return ownerRef != nil && ownerRef.Kind == "ReplicaSet" && strings.HasPrefix(ownerRef.Name, deploy.Name+"-")
+}
+
+func podReady(pod *v1.Pod) bool {
+	if pod.Status.Phase != v1.PodRunning || pod.Status.PodIP == "" {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// adminClients returns an API client for every ready pod of a grafana instance,
+// authenticated with its current admin credentials.
+func adminClients(kubeClientset kubernetes.Interface, secretLister corev1lister.SecretLister, grafana *aimsv1.Grafana) ([]*grafanaapi.Client, error) {
+	user, password := grafana.Spec.Username, grafana.Spec.Password
+	if ref := util.CredentialsRef(grafana); ref != nil {
+		secret, err := secretLister.Secrets(grafana.Namespace).Get(ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		userKey, passwordKey := util.CredentialsKeys(ref)
+		user, password = string(secret.Data[userKey]), string(secret.Data[passwordKey])
+	}
+
+	endpoints, err := grafanaEndpoints(kubeClientset, grafana)
+	if err != nil {
+		return nil, err
+	}
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("no ready pods for grafana %s/%s", grafana.Namespace, grafana.Name)
+	}
+
+	clients := []*grafanaapi.Client{}
+	for _, endpoint := range endpoints {
+		clients = append(clients, grafanaapi.NewClient(endpoint, user, password))
+	}
+	return clients, nil
+}
@@ -0,0 +1,85 @@
+// crd-gen embeds the CustomResourceDefinitions controller-gen renders into config/crd in the
+// operator, which installs or validates them at startup. Run it from the repository root after
+// regenerating the manifests:
+//
+//	controller-gen crd paths=./pkg/apis/... output:crd:dir=config/crd
+//	go run ./hack/crd-gen
+//
+// With -verify it only fails when the embedded copy is out of date.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	manifestGlob  = "config/crd/aims.cisco.com_*.yaml"
+	generatedFile = "pkg/crd/zz_generated.crds.go"
+	header        = "Code generated by hack/crd-gen from config/crd. DO NOT EDIT."
+)
+
+func main() {
+	verify := flag.Bool("verify", false, "Fail when the embedded CRDs are out of date instead of writing them.")
+	flag.Parse()
+
+	manifests, err := filepath.Glob(manifestGlob)
+	if err != nil {
+		fail(err)
+	}
+	if len(manifests) == 0 {
+		fail(fmt.Errorf("no manifests match %s, run controller-gen first", manifestGlob))
+	}
+
+	source := &bytes.Buffer{}
+	fmt.Fprintf(source, "// %s\n\npackage crd\n\n", header)
+	fmt.Fprintf(source, "// generated holds the CustomResourceDefinitions rendered from the API types, as JSON\n")
+	fmt.Fprintf(source, "var generated = []string{\n")
+
+	for _, manifest := range manifests {
+		data, err := ioutil.ReadFile(manifest)
+		if err != nil {
+			fail(err)
+		}
+		obj := map[string]interface{}{}
+		if err = yaml.Unmarshal(data, &obj); err != nil {
+			fail(fmt.Errorf("%s: %s", manifest, err))
+		}
+
+		data, err = json.MarshalIndent(obj, "", "  ")
+		if err != nil {
+			fail(err)
+		}
+		if strings.Contains(string(data), "`") {
+			fmt.Fprintf(source, "\t%s,\n", strconv.Quote(string(data)))
+		} else {
+			fmt.Fprintf(source, "\t`%s`,\n", data)
+		}
+	}
+	fmt.Fprintf(source, "}\n")
+
+	current, err := ioutil.ReadFile(generatedFile)
+	if err == nil && bytes.Equal(current, source.Bytes()) {
+		return
+	}
+	if *verify {
+		fail(fmt.Errorf("out of date, run go run ./hack/crd-gen: %s", generatedFile))
+	}
+	if err = ioutil.WriteFile(generatedFile, source.Bytes(), 0644); err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+	"github.com/dichque/grafana-operator/pkg/util"
+)
+
+// routesAvailable reports whether the cluster serves OpenShift Routes
+func routesAvailable(client discovery.DiscoveryInterface) (bool, error) {
+	_, err := client.ServerResourcesForGroupVersion(util.RouteGVR.GroupVersion().String())
+	if err != nil && errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// reconcileIngress exposes the instance through a Route on OpenShift and an Ingress
+// elsewhere, and publishes the external url in the status. Leftovers of a removed
+// spec.ingress are pruned.
+func (c *Controller) reconcileIngress(grafana *aimsv1.Grafana) error {
+	if grafana.Spec.Ingress == nil {
+		grafana.Status.URL = ""
+		return nil
+	}
+
+	var err error
+	if c.routesAvailable {
+		err = c.reconcileRoute(grafana)
+	} else {
+		err = c.reconcileIngressObject(grafana)
+	}
+	if err != nil {
+		return err
+	}
+
+	grafana.Status.URL = util.ExternalURL(grafana)
+	return nil
+}
+
+func (c *Controller) reconcileIngressObject(grafana *aimsv1.Grafana) error {
+	ing := util.Ingress(grafana)
+	found, err := c.ingressLister.Ingresses(ing.Namespace).Get(ing.Name)
+	if err != nil && errors.IsNotFound(err) {
+		_, err = c.kubeClientset.NetworkingV1beta1().Ingresses(ing.Namespace).Create(ing)
+		if err != nil {
+			return err
+		}
+		klog.Infof("ingress created: %s", ing.Name)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	owned := found.DeepCopy()
+	adopted, err := claim(c.recorder, grafana, "Ingress", owned)
+	if err != nil {
+		return err
+	}
+	updated, changed := util.MergeIngress(owned, ing)
+	if !changed && !adopted {
+		return nil
+	}
+	_, err = c.kubeClientset.NetworkingV1beta1().Ingresses(updated.Namespace).Update(updated)
+	if err != nil {
+		return err
+	}
+	klog.Infof("ingress updated: %s", updated.Name)
+	return nil
+}
+
+// reconcileRoute creates the Route of the instance, or brings the live one in line with it
+func (c *Controller) reconcileRoute(grafana *aimsv1.Grafana) error {
+	var tlsSecret *v1.Secret
+	if name := grafana.Spec.Ingress.TLSSecretName; name != "" {
+		secret, err := c.secretLister.Secrets(grafana.Namespace).Get(name)
+		if err != nil {
+			c.recorder.Eventf(grafana, v1.EventTypeWarning, "TLSSecretError", "unable to read tls secret %s: %s", name, err)
+			return err
+		}
+		tlsSecret = secret
+	}
+
+	route := util.Route(grafana, tlsSecret)
+	routes := c.dynamicClient.Resource(util.RouteGVR).Namespace(route.GetNamespace())
+	obj, err := c.routeLister.ByNamespace(route.GetNamespace()).Get(route.GetName())
+	if err != nil && errors.IsNotFound(err) {
+		_, err = routes.Create(route, metav1.CreateOptions{})
+		if err != nil {
+			return err
+		}
+		klog.Infof("route created: %s", route.GetName())
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	found, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("route %s/%s has unexpected type %T", route.GetNamespace(), route.GetName(), obj)
+	}
+	owned := found.DeepCopy()
+	adopted, err := claim(c.recorder, grafana, "Route", owned)
+	if err != nil {
+		return err
+	}
+	updated, changed := util.MergeRoute(owned, route)
+	if !changed && !adopted {
+		return nil
+	}
+	_, err = routes.Update(updated, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	klog.Infof("route updated: %s", updated.GetName())
+	return nil
+}
+
+// enqueueIngress takes an ingress and enqueues the Grafana object controlling it
+func (c *Controller) enqueueIngress(obj interface{}, a actionType) {
+	var ing *networkingv1beta1.Ingress
+	var ok bool
+	action = a
+
+	if ing, ok = obj.(*networkingv1beta1.Ingress); !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("error decoding ingress, invalid type"))
+			return
+		}
+		ing, ok = tombstone.Obj.(*networkingv1beta1.Ingress)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("error decoding ingress tombstone, invalid type"))
+			return
+		}
+		klog.V(4).Infof("Recovered deleted ingress '%s' from tombstone", ing.GetName())
+	}
+	if ownerRef := metav1.GetControllerOf(ing); ownerRef != nil {
+		if ownerRef.Kind != "Grafana" {
+			return
+		}
+
+		grafana, err := c.gLister.Grafanas(ing.GetNamespace()).Get(ownerRef.Name)
+		if err != nil {
+			klog.V(4).Infof("ignoring orphaned ingress '%s' of Grafana '%s'", ing.GetSelfLink(), ownerRef.Name)
+			return
+		}
+
+		klog.Infof("enqueuing Grafana %s/%s because of ingress change", grafana.Namespace, grafana.Name)
+		c.enqueueGrafana(grafana, action)
+	}
+}
+
+// enqueueRoute takes a route and enqueues the Grafana object controlling it
+func (c *Controller) enqueueRoute(obj interface{}, a actionType) {
+	action = a
+
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+		klog.V(4).Infof("Recovered deleted route '%s' from tombstone", tombstone.Key)
+	}
+	route, err := meta.Accessor(obj)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("error decoding route, invalid type"))
+		return
+	}
+	if ownerRef := metav1.GetControllerOf(route); ownerRef != nil {
+		if ownerRef.Kind != "Grafana" {
+			return
+		}
+
+		grafana, err := c.gLister.Grafanas(route.GetNamespace()).Get(ownerRef.Name)
+		if err != nil {
+			klog.V(4).Infof("ignoring orphaned route '%s' of Grafana '%s'", route.GetSelfLink(), ownerRef.Name)
+			return
+		}
+
+		klog.Infof("enqueuing Grafana %s/%s because of route change", grafana.Namespace, grafana.Name)
+		c.enqueueGrafana(grafana, action)
+	}
+}
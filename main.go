@@ -2,34 +2,67 @@ package main
 
 import (
 	"flag"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog"
 
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
 	clientset "github.com/dichque/grafana-operator/pkg/client/clientset/versioned"
 	ginformers "github.com/dichque/grafana-operator/pkg/client/informers/externalversions"
+	"github.com/dichque/grafana-operator/pkg/crd"
+	"github.com/dichque/grafana-operator/pkg/defaults"
+	"github.com/dichque/grafana-operator/pkg/util"
+	"github.com/dichque/grafana-operator/pkg/webhook"
 )
 
+// serviceAccountNamespace holds the namespace of the pod running in-cluster
+const serviceAccountNamespace string = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
 var (
 	masterURL  string
 	kubeconfig string
+	crdMode    string
+
+	defaultsFile string
+
+	webhookPort       int
+	webhookService    string
+	webhookNamespace  string
+	webhookCertSecret string
+	webhookConfig     string
 )
 
 func main() {
 	flag.StringVar(&kubeconfig, "kubeconfig", defaultKubeconfig(), "Path to a kubeconfig. Only required if out-of-cluster.")
 	flag.StringVar(&masterURL, "master", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
+	flag.StringVar(&crdMode, "crd", crd.ModeInstall, "What to do with the CRDs at startup: install them, validate that the installed ones match the API types and exit otherwise, or none. A failed install is logged and the operator runs with the installed CRDs.")
+	flag.StringVar(&defaultsFile, "defaults", "", "Path to a YAML file with the defaults of Grafana resources, such as the image of this cluster. Unset values keep the builtin defaults. Digests are resolved with anonymous registry access only, private images have to be pinned in its digests.")
+	flag.IntVar(&webhookPort, "webhook-port", 9443, "The port serving the admission webhooks. 0 disables them.")
+	flag.StringVar(&webhookService, "webhook-service", "grafana-operator-webhook", "The Service the API server reaches the admission webhooks through.")
+	flag.StringVar(&webhookNamespace, "webhook-namespace", defaultNamespace(), "The namespace of the webhook Service and certificate secret. Defaults to the namespace of the operator pod.")
+	flag.StringVar(&webhookCertSecret, "webhook-cert-secret", "grafana-operator-webhook-cert", "The secret holding the webhook serving certificate, created and rotated by the operator.")
+	flag.StringVar(&webhookConfig, "webhook-config", "grafana-operator", "The ValidatingWebhookConfiguration and MutatingWebhookConfiguration whose caBundle the operator maintains.")
 
 	klog.InitFlags(nil)
 
 	flag.Parse()
 
+	grafanaDefaults, err := defaults.Load(defaultsFile)
+	if err != nil {
+		klog.Fatalf("Error loading defaults: %s", err.Error())
+	}
+
 	cfg, err := rest.InClusterConfig()
 	if err != nil {
 		cfg, err = clientcmd.BuildConfigFromFlags(masterURL, kubeconfig)
@@ -48,16 +81,100 @@ func main() {
 		klog.Fatalf("Error building cnat clientset: %s", err.Error())
 	}
 
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		klog.Fatalf("Error building dynamic client: %s", err.Error())
+	}
+
+	if err = crd.Ensure(dynamicClient, crdMode); err != nil && crdMode == crd.ModeInstall {
+		// Exiting would crash-loop the operator until someone installs the CRDs by hand
+		klog.Errorf("Error installing custom resource definitions, running with the installed ones: %s", err.Error())
+	} else if err != nil {
+		klog.Fatalf("Error checking custom resource definitions: %s", err.Error())
+	}
+
+	if webhookPort != 0 && webhookNamespace == "" {
+		klog.Warningf("admission webhooks disabled: set -webhook-namespace when running out-of-cluster")
+	} else if webhookPort != 0 {
+		certs := webhook.NewCertManager(kubeClient, dynamicClient, webhookNamespace, webhookCertSecret, webhookService)
+		certs.ValidatingWebhook = webhookConfig
+		certs.MutatingWebhook = webhookConfig
+		certs.ConversionCRDs = []string{aimsv1.Resource("grafanas").String()}
+		server := webhook.NewServer(webhookPort, certs)
+		server.Handle(webhook.ValidateGrafanaPath, webhook.ValidateGrafana(grafanaClient))
+		server.Handle(webhook.DefaultGrafanaPath, webhook.DefaultGrafana(grafanaDefaults, defaults.NewPinner(grafanaDefaults)))
+		server.HandleConversion(webhook.ConvertPath)
+
+		go certs.Run(wait.NeverStop)
+		go func() {
+			if err := server.Run(wait.NeverStop); err != nil {
+				klog.Fatalf("Error serving admission webhooks: %s", err.Error())
+			}
+		}()
+	}
+
+	useRoutes, err := routesAvailable(kubeClient.Discovery())
+	if err != nil {
+		klog.Fatalf("Error discovering openshift routes: %s", err.Error())
+	}
+	klog.Infof("exposing grafana through openshift routes: %t", useRoutes)
+
 	deployInformerFactory := kubeinformers.NewSharedInformerFactory(kubeClient, time.Minute*1)
 	configMapInformerFactory := kubeinformers.NewSharedInformerFactory(kubeClient, time.Minute*1)
+	secretInformerFactory := kubeinformers.NewSharedInformerFactory(kubeClient, time.Minute*1)
+	serviceInformerFactory := kubeinformers.NewSharedInformerFactory(kubeClient, time.Minute*1)
+	ingressInformerFactory := kubeinformers.NewSharedInformerFactory(kubeClient, time.Minute*1)
+	hpaInformerFactory := kubeinformers.NewSharedInformerFactory(kubeClient, time.Minute*1)
 	grafanaInformerFactory := ginformers.NewSharedInformerFactory(grafanaClient, time.Minute*1)
+	routeInformerFactory := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, time.Minute*1)
+
+	var routeInformer kubeinformers.GenericInformer
+	if useRoutes {
+		routeInformer = routeInformerFactory.ForResource(util.RouteGVR)
+	}
 
 	controller := NewController(kubeClient, grafanaClient, grafanaInformerFactory.Aims().V1().Grafanas(),
-		deployInformerFactory.Apps().V1().Deployments(), configMapInformerFactory.Core().V1().ConfigMaps())
+		deployInformerFactory.Apps().V1().Deployments(), configMapInformerFactory.Core().V1().ConfigMaps(),
+		secretInformerFactory.Core().V1().Secrets(), grafanaInformerFactory.Aims().V1().GrafanaDataSources(),
+		serviceInformerFactory.Core().V1().Services(), ingressInformerFactory.Networking().V1beta1().Ingresses(),
+		hpaInformerFactory.Autoscaling().V1().HorizontalPodAutoscalers(), dynamicClient, routeInformer, grafanaDefaults.Image)
+
+	dashboardController := NewDashboardController(kubeClient, grafanaClient, grafanaInformerFactory.Aims().V1().Grafanas(),
+		grafanaInformerFactory.Aims().V1().GrafanaDashboards(), grafanaInformerFactory.Aims().V1().GrafanaFolders(),
+		configMapInformerFactory.Core().V1().ConfigMaps())
+
+	folderController := NewFolderController(kubeClient, grafanaClient, grafanaInformerFactory.Aims().V1().Grafanas(),
+		grafanaInformerFactory.Aims().V1().GrafanaFolders(), secretInformerFactory.Core().V1().Secrets())
+
+	dataSourceController := NewDataSourceController(kubeClient, grafanaClient, grafanaInformerFactory.Aims().V1().Grafanas(),
+		grafanaInformerFactory.Aims().V1().GrafanaDataSources())
 
 	deployInformerFactory.Start(wait.NeverStop)
 	configMapInformerFactory.Start(wait.NeverStop)
+	secretInformerFactory.Start(wait.NeverStop)
+	serviceInformerFactory.Start(wait.NeverStop)
+	ingressInformerFactory.Start(wait.NeverStop)
+	hpaInformerFactory.Start(wait.NeverStop)
 	grafanaInformerFactory.Start(wait.NeverStop)
+	routeInformerFactory.Start(wait.NeverStop)
+
+	go func() {
+		if err := dashboardController.Run(1, wait.NeverStop); err != nil {
+			klog.Fatalf("Error running dashboard controller: %s", err.Error())
+		}
+	}()
+
+	go func() {
+		if err := folderController.Run(1, wait.NeverStop); err != nil {
+			klog.Fatalf("Error running folder controller: %s", err.Error())
+		}
+	}()
+
+	go func() {
+		if err := dataSourceController.Run(1, wait.NeverStop); err != nil {
+			klog.Fatalf("Error running datasource controller: %s", err.Error())
+		}
+	}()
 
 	if err = controller.Run(2, wait.NeverStop); err != nil {
 		klog.Fatalf("Error running controller: %s", err.Error())
@@ -76,3 +193,15 @@ func defaultKubeconfig() string {
 	}
 	return filepath.Join(home, ".kube", "config")
 }
+
+// defaultNamespace returns the namespace of the operator pod, empty out-of-cluster
+func defaultNamespace() string {
+	if ns := os.Getenv("POD_NAMESPACE"); ns != "" {
+		return ns
+	}
+	data, err := ioutil.ReadFile(serviceAccountNamespace)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog"
+
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+	"github.com/dichque/grafana-operator/pkg/conditions"
+)
+
+// adoptAnnotation set to "true" on an existing object without a controller lets an instance
+// take it over instead of reporting a conflict
+const adoptAnnotation string = "aims.cisco.com/adopt"
+
+// conflictError reports a child object the instance would have to overwrite without owning it
+type conflictError struct {
+	kind  string
+	name  string
+	owner *metav1.OwnerReference
+}
+
+func (e *conflictError) Error() string {
+	if e.owner != nil {
+		return fmt.Sprintf("%s %s is controlled by %s %s", e.kind, e.name, e.owner.Kind, e.owner.Name)
+	}
+	return fmt.Sprintf("%s %s exists and is not managed by this instance, annotate it with %s=true to adopt it", e.kind, e.name, adoptAnnotation)
+}
+
+// claim makes sure the instance controls obj before it is mutated. An object without a
+// controller is adopted by adding the owner reference to obj when it carries the adopt
+// annotation, so callers pass a copy and persist it when adopted is true. Any other object
+// yields a conflictError.
+func claim(recorder record.EventRecorder, grafana *aimsv1.Grafana, kind string, obj metav1.Object) (adopted bool, err error) {
+	if metav1.IsControlledBy(obj, grafana) {
+		return false, nil
+	}
+	if owner := metav1.GetControllerOf(obj); owner != nil {
+		return false, &conflictError{kind: kind, name: obj.GetName(), owner: owner}
+	}
+	if obj.GetAnnotations()[adoptAnnotation] != "true" {
+		return false, &conflictError{kind: kind, name: obj.GetName()}
+	}
+
+	owner := metav1.NewControllerRef(grafana, aimsv1.SchemeGroupVersion.WithKind("Grafana"))
+	obj.SetOwnerReferences(append(obj.GetOwnerReferences(), *owner))
+	klog.Infof("%s adopted: %s", kind, obj.GetName())
+	recorder.Eventf(grafana, v1.EventTypeNormal, "Adopted", "%s %s adopted", kind, obj.GetName())
+	return true, nil
+}
+
+// setConflictCondition records an ownership conflict in the status of the instance, or its
+// resolution when err is nil and a conflict was reported before
+func (c *Controller) setConflictCondition(grafana *aimsv1.Grafana, err *conflictError) {
+	if err == nil {
+		if conditions.Find(grafana.Status.Conditions, aimsv1.ConditionTypeConflict) == nil {
+			return
+		}
+		conditions.Set(&grafana.Status.Conditions, aimsv1.GrafanaCondition{
+			Type:    aimsv1.ConditionTypeConflict,
+			Status:  aimsv1.ConditionStatusFalse,
+			Reason:  aimsv1.ConditionReasonConflictResolved,
+			Message: "all child objects are controlled by this instance",
+		})
+		return
+	}
+
+	c.recorder.Event(grafana, v1.EventTypeWarning, "Conflict", err.Error())
+	conditions.Set(&grafana.Status.Conditions, aimsv1.GrafanaCondition{
+		Type:    aimsv1.ConditionTypeConflict,
+		Status:  aimsv1.ConditionStatusTrue,
+		Reason:  aimsv1.ConditionReasonNotOwned,
+		Message: err.Error(),
+	})
+}
@@ -31,12 +31,18 @@ var (
 )
 
 // addKnownTypes adds our types to the API scheme by registering
-// Grafana and GrafanaList
+// Grafana, GrafanaDashboard, GrafanaDataSource, GrafanaFolder and their lists
 func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(
 		SchemeGroupVersion,
 		&Grafana{},
 		&GrafanaList{},
+		&GrafanaDashboard{},
+		&GrafanaDashboardList{},
+		&GrafanaDataSource{},
+		&GrafanaDataSourceList{},
+		&GrafanaFolder{},
+		&GrafanaFolderList{},
 	)
 
 	// register the type in the scheme
@@ -2,39 +2,246 @@ package v1
 
 import (
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
 
 // Grafana describes a Grafana resource
+// +kubebuilder:resource:path=grafanas,singular=grafana,shortName=graf
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.selector
+// +kubebuilder:printcolumn:name="Image",type=string,JSONPath=".spec.image"
+// +kubebuilder:printcolumn:name="Replicas",type=integer,JSONPath=".spec.replicas"
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=".status.url"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
 type Grafana struct {
 	// TypeMeta is the metadata for the resource, like kind and apiversion
 	meta_v1.TypeMeta   `json:",inline"`
 	meta_v1.ObjectMeta `json:"metadata,omitempty"`
 
+	// +optional
 	Spec   GrafanaSpec   `json:"spec"`
 	Status GrafanaStatus `json:"status,omitempty"`
 }
 
-// GrafanaSpec is the spec for a grafana resource
+// GrafanaSpec is the spec for a grafana resource. More than one replica needs an external
+// database.
 type GrafanaSpec struct {
-	Image         string `json:"image,omitempty"`
-	Replicas      *int32 `json:"replicas,omitempty"`
-	Username      string `json:"user,omitempty"`
-	Password      string `json:"password,omitempty"`
+	// Image defaults to the image of the operator configuration, and is pinned to a digest
+	// on admission when the operator is configured to
+	Image string `json:"image,omitempty"`
+
+	// Replicas defaults to the operator configuration
+	// +kubebuilder:validation:Minimum=1
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Username defaults to the operator configuration unless CredentialsSecretRef is set
+	Username string `json:"user,omitempty"`
+
+	// Password is stored in the operator managed admin secret, and generated there when
+	// neither it nor CredentialsSecretRef is set
+	Password string `json:"password,omitempty"`
+
+	// PrometheusURL defaults to the operator configuration
 	PrometheusURL string `json:"prometheus_url,omitempty"`
+
+	// Datasources are provisioned alongside the prometheus_url shorthand
+	Datasources []GrafanaDatasource `json:"datasources,omitempty"`
+
+	// DatasourceSelector selects GrafanaDataSource resources to provision, from the instance
+	// namespace and from the namespaces the datasources allow through allowedNamespaces
+	DatasourceSelector *meta_v1.LabelSelector `json:"datasourceSelector,omitempty"`
+
+	// CredentialsSecretRef takes precedence over Username and Password
+	CredentialsSecretRef *CredentialsSecretRef `json:"credentialsSecretRef,omitempty"`
+
+	// DashboardBundles selects the built-in dashboard bundles to mount. When unset the
+	// kafka and zookeeper bundles are mounted, an empty list mounts none.
+	// +kubebuilder:validation:items:Enum=kafka;zookeeper;rabbitmq;burrow
+	DashboardBundles []string `json:"dashboardBundles,omitempty"`
+
+	// Config overrides grafana.ini keys by section. Credentials such as the admin
+	// password are rejected here and must be set through secrets.
+	Config map[string]map[string]string `json:"config,omitempty"`
+
+	// Storage persists /var/lib/grafana in a PersistentVolumeClaim instead of an EmptyDir
+	Storage *GrafanaStorage `json:"storage,omitempty"`
+
+	// Database moves grafana state to an external database, required for more than one replica
+	Database *GrafanaDatabase `json:"database,omitempty"`
+
+	// Service customizes the Service exposing the instance
+	Service *GrafanaService `json:"service,omitempty"`
+
+	// Ingress exposes the instance outside of the cluster, through a Route on OpenShift
+	Ingress *GrafanaIngress `json:"ingress,omitempty"`
+
+	// Resources of the grafana container
+	Resources v1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// GrafanaIngress describes the external endpoint of an instance
+type GrafanaIngress struct {
+	Host string `json:"host"`
+
+	// Path defaults to /, grafana is configured to serve from any other path
+	Path string `json:"path,omitempty"`
+
+	// TLSSecretName names a kubernetes.io/tls secret, enabling https on the endpoint
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// GrafanaService describes the Service the operator creates for an instance
+type GrafanaService struct {
+	// Type defaults to ClusterIP
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	Type v1.ServiceType `json:"type,omitempty"`
+
+	// Port defaults to the grafana port 3000
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port     int32 `json:"port,omitempty"`
+	NodePort int32 `json:"nodePort,omitempty"`
+
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// +kubebuilder:validation:Enum=None;ClientIP
+	SessionAffinity v1.ServiceAffinity `json:"sessionAffinity,omitempty"`
+}
+
+// GrafanaDatabase describes the external database shared by the replicas of an instance
+type GrafanaDatabase struct {
+	Type DatabaseType `json:"type"`
+
+	// Host is the address of the database as host:port
+	Host string `json:"host"`
+	Name string `json:"name"`
+	User string `json:"user"`
+
+	// PasswordSecretRef selects the password of User in a secret of the instance namespace
+	PasswordSecretRef *v1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+
+	// SSLMode is passed to postgres as ssl_mode, e.g. disable, require or verify-full
+	SSLMode string `json:"sslMode,omitempty"`
+}
+
+// DatabaseType is an external database supported by grafana
+// +kubebuilder:validation:Enum=postgres;mysql
+type DatabaseType string
+
+// These are the supported external databases
+const (
+	DatabaseTypePostgres DatabaseType = "postgres"
+	DatabaseTypeMySQL    DatabaseType = "mysql"
+)
+
+// GrafanaStorage describes the claim holding the grafana data directory
+type GrafanaStorage struct {
+	Size             resource.Quantity `json:"size,omitempty"`
+	StorageClassName *string           `json:"storageClassName,omitempty"`
+
+	// AccessModes of the claim, ReadWriteOnce when empty. They also decide the deployment
+	// strategy when ExistingClaim is set.
+	// +kubebuilder:validation:items:Enum=ReadWriteOnce;ReadOnlyMany;ReadWriteMany
+	AccessModes []v1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+
+	// ExistingClaim mounts a claim managed outside of the operator instead of creating one
+	ExistingClaim string `json:"existingClaim,omitempty"`
+
+	// RetainPolicy decides whether the created claim is deleted along with the Grafana resource
+	RetainPolicy StorageRetainPolicy `json:"retainPolicy,omitempty"`
+}
+
+// StorageRetainPolicy decides what happens to the data claim when the Grafana resource is deleted
+// +kubebuilder:validation:Enum=Delete;Retain
+type StorageRetainPolicy string
+
+// These are the supported retain policies, Delete being the default
+const (
+	StorageRetainPolicyDelete StorageRetainPolicy = "Delete"
+	StorageRetainPolicyRetain StorageRetainPolicy = "Retain"
+)
+
+// GrafanaDatasource describes a datasource provisioned into grafana
+type GrafanaDatasource struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	URL  string `json:"url,omitempty"`
+
+	// +kubebuilder:validation:Enum=proxy;direct
+	Access         string                `json:"access,omitempty"`
+	IsDefault      bool                  `json:"isDefault,omitempty"`
+	JSONData       *runtime.RawExtension `json:"jsonData,omitempty"`
+	SecureJSONData map[string]string     `json:"secureJsonData,omitempty"`
+
+	// SecureJSONDataFrom sources secureJsonData values from secrets in the instance namespace
+	SecureJSONDataFrom map[string]DatasourceValueSource `json:"secureJsonDataFrom,omitempty"`
+}
+
+// DatasourceValueSource selects the source of a datasource value
+type DatasourceValueSource struct {
+	SecretKeyRef *v1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+}
+
+// CredentialsSecretRef selects the secret and keys holding the grafana admin credentials
+type CredentialsSecretRef struct {
+	Name string `json:"name"`
+	// +kubebuilder:default=user
+	UserKey string `json:"userKey,omitempty"`
+
+	// +kubebuilder:default=password
+	PasswordKey string `json:"passwordKey,omitempty"`
 }
 
 // GrafanaStatus defines the observed state of grafana custom resource
 type GrafanaStatus struct {
-	GStatus         v1.ConditionStatus `json:"gStatus,omitempty"`
+	// GStatus mirrors the status of the Ready condition
+	GStatus v1.ConditionStatus `json:"gStatus,omitempty"`
+
+	// LastUpdatedTime is when the operator last changed the status
 	LastUpdatedTime meta_v1.Time       `json:"lastUpdatedTime,omitempty"`
 	Conditions      []GrafanaCondition `json:"conditions,omitempty"`
+
+	// ObservedGeneration is the generation of the spec the status was computed from
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// AdminSecret names the secret holding the operator managed admin credentials
+	AdminSecret string `json:"adminSecret,omitempty"`
+
+	// AdminPasswordRotation is the last rotate-admin-password annotation value that was processed
+	AdminPasswordRotation string `json:"adminPasswordRotation,omitempty"`
+
+	// DataSources lists the namespace/name of the GrafanaDataSource resources provisioned
+	DataSources []string `json:"dataSources,omitempty"`
+
+	// URL is the external url of the instance when spec.ingress is set
+	URL string `json:"url,omitempty"`
+
+	// ConfigHash digests the configuration the pods run with, see the config-hash annotation
+	ConfigHash string `json:"configHash,omitempty"`
+
+	// LastRolloutTime is when the pod template of the deployment was last changed
+	LastRolloutTime *meta_v1.Time `json:"lastRolloutTime,omitempty"`
+
+	// Replicas is the number of pods of the deployment, read by the scale subresource
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// Selector selects the pods of the instance, in the string form the scale subresource
+	// and HorizontalPodAutoscalers expect
+	Selector string `json:"selector,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
 
 // GrafanaList is a list of Grafana resources
 type GrafanaList struct {
@@ -52,6 +259,23 @@ const (
 
 	// ConditionTypeGrafanaDeployment tracks deployment
 	ConditionTypeGrafanaDeployment ConditionType = "GrafanaDeployment"
+
+	// ConditionTypeAdminPasswordRotated tracks the last admin password rotation
+	ConditionTypeAdminPasswordRotated ConditionType = "AdminPasswordRotated"
+
+	// ConditionTypeReady tracks whether all desired replicas of the deployment are available
+	ConditionTypeReady ConditionType = "Ready"
+
+	// ConditionTypeReconciling is true while the operator works towards the spec, following
+	// the kstatus conventions
+	ConditionTypeReconciling ConditionType = "Reconciling"
+
+	// ConditionTypeStalled is true when the spec cannot be reconciled without a change from
+	// the user, following the kstatus conventions
+	ConditionTypeStalled ConditionType = "Stalled"
+
+	// ConditionTypeConflict tracks child objects the instance cannot manage as it does not own them
+	ConditionTypeConflict ConditionType = "Conflict"
 )
 
 // ConditionStatus we track
@@ -72,8 +296,24 @@ const (
 	ConditionReasonGrafanaConfigMapUpdate  ConditionReason = "ConfigMapUpdate"
 	ConditionReasonGrafanaDeploymentUpdate ConditionReason = "DeploymentUpdate"
 
+	ConditionReasonGrafanaConfigMapUpdateFailed  ConditionReason = "ConfigMapUpdateFailed"
+	ConditionReasonGrafanaDeploymentUpdateFailed ConditionReason = "DeploymentUpdateFailed"
+
 	ConditionReasonGrafanaConfigMapDelete  ConditionReason = "ConfigMapDelete"
 	ConditionReasonGrafanaDeploymentDelete ConditionReason = "DeploymentDelete"
+
+	ConditionReasonAdminPasswordRotated        ConditionReason = "PasswordRotated"
+	ConditionReasonAdminPasswordRotationFailed ConditionReason = "PasswordRotationFailed"
+
+	ConditionReasonReplicasAvailable   ConditionReason = "MinimumReplicasAvailable"
+	ConditionReasonReplicasUnavailable ConditionReason = "ReplicasUnavailable"
+
+	ConditionReasonReconciled     ConditionReason = "Reconciled"
+	ConditionReasonReconcileError ConditionReason = "ReconcileError"
+	ConditionReasonInvalidSpec    ConditionReason = "InvalidSpec"
+
+	ConditionReasonNotOwned         ConditionReason = "NotOwned"
+	ConditionReasonConflictResolved ConditionReason = "ConflictResolved"
 )
 
 // GrafanaCondition defines the observed state of grafana custom resource
@@ -84,3 +324,167 @@ type GrafanaCondition struct {
 	Message            string          `json:"message,omitempty"`
 	LastTransitionTime meta_v1.Time    `json:"lastTransitionTime,omitempty"`
 }
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+// GrafanaDashboard describes a dashboard attached to the matching Grafana resources
+// +kubebuilder:resource:path=grafanadashboards,singular=grafanadashboard,shortName=grafdash
+// +kubebuilder:subresource:status
+type GrafanaDashboard struct {
+	meta_v1.TypeMeta   `json:",inline"`
+	meta_v1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaDashboardSpec   `json:"spec"`
+	Status GrafanaDashboardStatus `json:"status,omitempty"`
+}
+
+// GrafanaDashboardSpec is the spec for a grafana dashboard resource
+type GrafanaDashboardSpec struct {
+	// JSON is the dashboard model as exported from grafana
+	JSON string `json:"json"`
+
+	// Folder names the GrafanaFolder of the namespace the dashboard is provisioned into. When
+	// no such GrafanaFolder exists it is used as the folder title.
+	Folder string `json:"folder,omitempty"`
+
+	// GrafanaSelector selects the Grafana resources of the namespace to attach to
+	GrafanaSelector *meta_v1.LabelSelector `json:"grafanaSelector,omitempty"`
+}
+
+// GrafanaDashboardStatus defines the observed state of grafana dashboard custom resource
+type GrafanaDashboardStatus struct {
+	// Instances lists the Grafana resources the dashboard is attached to
+	Instances []string `json:"instances,omitempty"`
+	Message   string   `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+// GrafanaDashboardList is a list of GrafanaDashboard resources
+type GrafanaDashboardList struct {
+	meta_v1.TypeMeta `json:",inline"`
+	meta_v1.ListMeta `json:"metadata,omitempty"`
+	Items            []GrafanaDashboard `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+// GrafanaDataSource describes a datasource provisioned into the Grafana resources selecting it
+// +kubebuilder:resource:path=grafanadatasources,singular=grafanadatasource,shortName=grafds
+// +kubebuilder:subresource:status
+type GrafanaDataSource struct {
+	meta_v1.TypeMeta   `json:",inline"`
+	meta_v1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaDataSourceSpec   `json:"spec"`
+	Status GrafanaDataSourceStatus `json:"status,omitempty"`
+}
+
+// GrafanaDataSourceSpec is the spec for a grafana datasource resource. Secrets referenced
+// by secureJsonDataFrom are read from the namespace of each selecting Grafana resource.
+type GrafanaDataSourceSpec struct {
+	GrafanaDatasource `json:",inline"`
+
+	// AllowedNamespaces lists the namespaces, besides its own, whose Grafana resources may
+	// select the datasource. "*" allows every namespace.
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+}
+
+// GrafanaDataSourceStatus defines the observed state of grafana datasource custom resource
+type GrafanaDataSourceStatus struct {
+	Instances []GrafanaDataSourceInstance `json:"instances,omitempty"`
+}
+
+// GrafanaDataSourceInstance reports the sync state of a datasource in one Grafana resource
+type GrafanaDataSourceInstance struct {
+	// Grafana is the namespace/name of the selecting Grafana resource
+	Grafana string `json:"grafana"`
+	Synced  bool   `json:"synced"`
+	Message string `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+// GrafanaDataSourceList is a list of GrafanaDataSource resources
+type GrafanaDataSourceList struct {
+	meta_v1.TypeMeta `json:",inline"`
+	meta_v1.ListMeta `json:"metadata,omitempty"`
+	Items            []GrafanaDataSource `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+// GrafanaFolder describes a dashboard folder created in the matching Grafana resources
+// +kubebuilder:resource:path=grafanafolders,singular=grafanafolder,shortName=grafolder
+// +kubebuilder:subresource:status
+type GrafanaFolder struct {
+	meta_v1.TypeMeta   `json:",inline"`
+	meta_v1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GrafanaFolderSpec   `json:"spec"`
+	Status GrafanaFolderStatus `json:"status,omitempty"`
+}
+
+// GrafanaFolderSpec is the spec for a grafana folder resource
+type GrafanaFolderSpec struct {
+	Title string `json:"title"`
+
+	// UID defaults to the name of the GrafanaFolder
+	// +kubebuilder:validation:MaxLength=40
+	UID string `json:"uid,omitempty"`
+
+	// Permissions replace the folder permissions when set
+	Permissions []FolderPermission `json:"permissions,omitempty"`
+
+	// GrafanaSelector selects the Grafana resources of the namespace to create the folder in
+	GrafanaSelector *meta_v1.LabelSelector `json:"grafanaSelector,omitempty"`
+}
+
+// FolderPermission grants a permission on the folder to one of a team, a role or a user
+type FolderPermission struct {
+	Team string `json:"team,omitempty"`
+
+	// +kubebuilder:validation:Enum=Viewer;Editor
+	Role string `json:"role,omitempty"`
+
+	User       string               `json:"user,omitempty"`
+	Permission FolderPermissionType `json:"permission"`
+}
+
+// FolderPermissionType is the level of access granted on a folder
+// +kubebuilder:validation:Enum=View;Edit;Admin
+type FolderPermissionType string
+
+// These are the folder permission levels supported by grafana
+const (
+	FolderPermissionView  FolderPermissionType = "View"
+	FolderPermissionEdit  FolderPermissionType = "Edit"
+	FolderPermissionAdmin FolderPermissionType = "Admin"
+)
+
+// GrafanaFolderStatus defines the observed state of grafana folder custom resource
+type GrafanaFolderStatus struct {
+	UID string `json:"uid,omitempty"`
+
+	// Instances lists the Grafana resources the folder is synced to
+	Instances []string `json:"instances,omitempty"`
+	Message   string   `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+// GrafanaFolderList is a list of GrafanaFolder resources
+type GrafanaFolderList struct {
+	meta_v1.TypeMeta `json:",inline"`
+	meta_v1.ListMeta `json:"metadata,omitempty"`
+	Items            []GrafanaFolder `json:"items"`
+}
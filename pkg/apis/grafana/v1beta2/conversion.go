@@ -0,0 +1,119 @@
+package v1beta2
+
+import (
+	"fmt"
+
+	v1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+)
+
+// specFields maps the spec fields of v1 to their path in v1beta2. Fields not listed keep their
+// name. Conversion moves JSON values rather than typed fields so that nothing is lost, such as
+// an empty dashboardBundles list that differs from an unset one.
+var specFields = []struct {
+	v1      string
+	v1beta2 []string
+}{
+	{"image", []string{"deployment", "image"}},
+	{"replicas", []string{"deployment", "replicas"}},
+	{"resources", []string{"deployment", "resources"}},
+	{"user", []string{"security", "adminUser"}},
+	{"password", []string{"security", "adminPassword"}},
+	{"credentialsSecretRef", []string{"security", "credentialsSecretRef"}},
+	{"prometheus_url", []string{"datasources", "prometheusURL"}},
+	{"datasources", []string{"datasources", "inline"}},
+	{"datasourceSelector", []string{"datasources", "selector"}},
+}
+
+// ConvertFromV1 converts the JSON object of a v1 Grafana to v1beta2 in place
+func ConvertFromV1(obj map[string]interface{}) error {
+	if err := checkVersion(obj, v1.SchemeGroupVersion.String()); err != nil {
+		return err
+	}
+
+	obj["apiVersion"] = SchemeGroupVersion.String()
+
+	spec, ok := obj["spec"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	converted := map[string]interface{}{}
+	moved := map[string]bool{}
+	for _, field := range specFields {
+		if value, ok := spec[field.v1]; ok {
+			setPath(converted, field.v1beta2, value)
+			moved[field.v1] = true
+		}
+	}
+	for key, value := range spec {
+		if !moved[key] {
+			converted[key] = value
+		}
+	}
+	obj["spec"] = converted
+	return nil
+}
+
+// ConvertToV1 converts the JSON object of a v1beta2 Grafana to v1 in place
+func ConvertToV1(obj map[string]interface{}) error {
+	if err := checkVersion(obj, SchemeGroupVersion.String()); err != nil {
+		return err
+	}
+	obj["apiVersion"] = v1.SchemeGroupVersion.String()
+
+	spec, ok := obj["spec"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	converted := map[string]interface{}{}
+	sections := map[string]bool{}
+	for _, field := range specFields {
+		if value, ok := getPath(spec, field.v1beta2); ok {
+			converted[field.v1] = value
+		}
+		sections[field.v1beta2[0]] = true
+	}
+	for key, value := range spec {
+		if !sections[key] {
+			converted[key] = value
+		}
+	}
+	obj["spec"] = converted
+	return nil
+}
+
+func checkVersion(obj map[string]interface{}, apiVersion string) error {
+	if kind := obj["kind"]; kind != "Grafana" {
+		return fmt.Errorf("cannot convert kind %v", kind)
+	}
+	if version := obj["apiVersion"]; version != apiVersion {
+		return fmt.Errorf("expected apiVersion %s, got %v", apiVersion, version)
+	}
+	return nil
+}
+
+// setPath sets value at path in obj, creating the objects along the path
+func setPath(obj map[string]interface{}, path []string, value interface{}) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := obj[key].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			obj[key] = next
+		}
+		obj = next
+	}
+	obj[path[len(path)-1]] = value
+}
+
+// getPath returns the value at path in obj
+func getPath(obj map[string]interface{}, path []string) (interface{}, bool) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := obj[key].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		obj = next
+	}
+	value, ok := obj[path[len(path)-1]]
+	return value, ok
+}
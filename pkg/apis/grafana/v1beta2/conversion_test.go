@@ -0,0 +1,218 @@
+package v1beta2
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	fuzz "github.com/google/gofuzz"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	v1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+)
+
+func decode(t *testing.T, data []byte) map[string]interface{} {
+	t.Helper()
+	obj := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&obj); err != nil {
+		t.Fatalf("decoding %s: %s", data, err)
+	}
+	return obj
+}
+
+func encode(t *testing.T, obj interface{}) []byte {
+	t.Helper()
+	data, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatalf("encoding %v: %s", obj, err)
+	}
+	return data
+}
+
+// decodesStrictly checks that every field of a converted object is known to the v1beta2 types
+func decodesStrictly(t *testing.T, obj map[string]interface{}) {
+	t.Helper()
+	decoder := json.NewDecoder(bytes.NewReader(encode(t, obj)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&Grafana{}); err != nil {
+		t.Errorf("converted object does not decode as v1beta2: %s", err)
+	}
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		v1      string
+		v1beta2 string
+	}{
+		{
+			name:    "no spec",
+			v1:      `{"apiVersion":"aims.cisco.com/v1","kind":"Grafana","metadata":{"name":"a"}}`,
+			v1beta2: `{"apiVersion":"aims.cisco.com/v1beta2","kind":"Grafana","metadata":{"name":"a"}}`,
+		},
+		{
+			name:    "empty spec",
+			v1:      `{"apiVersion":"aims.cisco.com/v1","kind":"Grafana","spec":{}}`,
+			v1beta2: `{"apiVersion":"aims.cisco.com/v1beta2","kind":"Grafana","spec":{}}`,
+		},
+		{
+			name: "grouped fields",
+			v1: `{"apiVersion":"aims.cisco.com/v1","kind":"Grafana","spec":{
+				"image":"grafana/grafana:6.7.3","replicas":2,"resources":{"limits":{"memory":"512Mi"}},
+				"user":"admin","password":"secret","credentialsSecretRef":{"name":"admin"},
+				"prometheus_url":"http://prometheus:9090","datasources":[{"name":"loki","type":"loki"}],
+				"datasourceSelector":{"matchLabels":{"shared":"true"}}}}`,
+			v1beta2: `{"apiVersion":"aims.cisco.com/v1beta2","kind":"Grafana","spec":{
+				"deployment":{"image":"grafana/grafana:6.7.3","replicas":2,"resources":{"limits":{"memory":"512Mi"}}},
+				"security":{"adminUser":"admin","adminPassword":"secret","credentialsSecretRef":{"name":"admin"}},
+				"datasources":{"prometheusURL":"http://prometheus:9090","inline":[{"name":"loki","type":"loki"}],
+				"selector":{"matchLabels":{"shared":"true"}}}}}`,
+		},
+		{
+			name:    "fields keeping their name",
+			v1:      `{"apiVersion":"aims.cisco.com/v1","kind":"Grafana","spec":{"dashboardBundles":[],"config":{"auth":{"disable_login_form":"true"}},"storage":{"size":"5Gi"}}}`,
+			v1beta2: `{"apiVersion":"aims.cisco.com/v1beta2","kind":"Grafana","spec":{"dashboardBundles":[],"config":{"auth":{"disable_login_form":"true"}},"storage":{"size":"5Gi"}}}`,
+		},
+		{
+			name:    "unknown fields",
+			v1:      `{"apiVersion":"aims.cisco.com/v1","kind":"Grafana","spec":{"future":{"a":1}},"status":{"replicas":1}}`,
+			v1beta2: `{"apiVersion":"aims.cisco.com/v1beta2","kind":"Grafana","spec":{"future":{"a":1}},"status":{"replicas":1}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj := decode(t, []byte(test.v1))
+			if err := ConvertFromV1(obj); err != nil {
+				t.Fatalf("converting from v1: %s", err)
+			}
+			if expected := decode(t, []byte(test.v1beta2)); !reflect.DeepEqual(obj, expected) {
+				t.Errorf("expected %s, got %s", encode(t, expected), encode(t, obj))
+			}
+
+			if err := ConvertToV1(obj); err != nil {
+				t.Fatalf("converting to v1: %s", err)
+			}
+			if expected := decode(t, []byte(test.v1)); !reflect.DeepEqual(obj, expected) {
+				t.Errorf("round trip: expected %s, got %s", encode(t, expected), encode(t, obj))
+			}
+		})
+	}
+}
+
+// TestConvertBaselineObjects converts Grafana resources as the API server stored them under the
+// first CRD, which defaulted the image, user and password, to v1beta2 and back
+func TestConvertBaselineObjects(t *testing.T) {
+	tests := []struct {
+		name     string
+		v1       string
+		security string
+	}{
+		{
+			name: "defaulted",
+			v1: `{"apiVersion":"aims.cisco.com/v1","kind":"Grafana",
+				"metadata":{"name":"grafana","namespace":"kafka","uid":"8b1d6c3e-5a55-4f0e-9d1e-7a3c2f1b0a9d","resourceVersion":"123456","generation":1,
+				"creationTimestamp":"2020-05-04T10:00:00Z","annotations":{"kubectl.kubernetes.io/last-applied-configuration":"{}"}},
+				"spec":{"image":"containers.cisco.com/intps/grafana:latest","replicas":1,"user":"aims","password":"aims",
+				"prometheus_url":"http://prometheus-operated:9090"},
+				"status":{"gStatus":"True","lastUpdatedTime":"2020-05-04T10:01:00Z"}}`,
+			security: `{"adminUser":"aims","adminPassword":"aims"}`,
+		},
+		{
+			name: "example",
+			v1: `{"apiVersion":"aims.cisco.com/v1","kind":"Grafana","metadata":{"name":"grafana-sample-1"},
+				"spec":{"replicas":1,"image":"grafana/grafana:6.0.0","user":"aims","password":"password",
+				"prometheus_url":"http://prometheus-operated:9090"}}`,
+			security: `{"adminUser":"aims","adminPassword":"password"}`,
+		},
+		{
+			name:     "empty password",
+			v1:       `{"apiVersion":"aims.cisco.com/v1","kind":"Grafana","metadata":{"name":"a"},"spec":{"user":"admin","password":""}}`,
+			security: `{"adminUser":"admin","adminPassword":""}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj := decode(t, []byte(test.v1))
+			if err := ConvertFromV1(obj); err != nil {
+				t.Fatalf("converting from v1: %s", err)
+			}
+			decodesStrictly(t, obj)
+			if security := obj["spec"].(map[string]interface{})["security"]; !reflect.DeepEqual(security, decode(t, []byte(test.security))) {
+				t.Errorf("expected security %s, got %s", test.security, encode(t, security))
+			}
+
+			if err := ConvertToV1(obj); err != nil {
+				t.Fatalf("converting to v1: %s", err)
+			}
+			if expected := decode(t, []byte(test.v1)); !reflect.DeepEqual(obj, expected) {
+				t.Errorf("round trip: expected %s, got %s", encode(t, expected), encode(t, obj))
+			}
+		})
+	}
+}
+
+func TestConvertWrongVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		obj     string
+		convert func(map[string]interface{}) error
+	}{
+		{name: "v1beta2 from v1", obj: `{"apiVersion":"aims.cisco.com/v1beta2","kind":"Grafana"}`, convert: ConvertFromV1},
+		{name: "v1 to v1", obj: `{"apiVersion":"aims.cisco.com/v1","kind":"Grafana"}`, convert: ConvertToV1},
+		{name: "other kind", obj: `{"apiVersion":"aims.cisco.com/v1","kind":"GrafanaDashboard"}`, convert: ConvertFromV1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.convert(decode(t, []byte(test.obj))); err == nil {
+				t.Errorf("expected an error converting %s", test.obj)
+			}
+		})
+	}
+}
+
+// TestConvertRoundTripFuzz converts random v1 resources to v1beta2 and back
+func TestConvertRoundTripFuzz(t *testing.T) {
+	f := fuzz.New().NilChance(0.3).NumElements(0, 2).Funcs(
+		func(q *resource.Quantity, c fuzz.Continue) {
+			*q = *resource.NewQuantity(c.Int63n(1<<20), resource.BinarySI)
+		},
+		func(raw *runtime.RawExtension, c fuzz.Continue) {
+			raw.Raw = []byte(`{"key":` + string(mustMarshal(c.RandString())) + `}`)
+		},
+	)
+
+	for i := 0; i < 500; i++ {
+		grafana := &v1.Grafana{}
+		f.Fuzz(&grafana.Spec)
+		grafana.APIVersion, grafana.Kind = v1.SchemeGroupVersion.String(), "Grafana"
+		grafana.Name = "fuzz"
+
+		data := encode(t, grafana)
+		obj := decode(t, data)
+		if err := ConvertFromV1(obj); err != nil {
+			t.Fatalf("converting %s from v1: %s", data, err)
+		}
+		decodesStrictly(t, obj)
+		if err := ConvertToV1(obj); err != nil {
+			t.Fatalf("converting %s to v1: %s", data, err)
+		}
+		if expected := decode(t, data); !reflect.DeepEqual(obj, expected) {
+			t.Fatalf("round trip: expected %s, got %s", data, encode(t, obj))
+		}
+	}
+}
+
+func mustMarshal(v interface{}) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
@@ -0,0 +1,6 @@
+// +k8s:deepcopy-gen=package
+// +groupName=aims.cisco.com
+
+// Package v1beta2 is the cleaned up version of the Grafana API. Resources are stored as v1 and
+// converted by the conversion webhook of the operator.
+package v1beta2
@@ -0,0 +1,290 @@
+package v1beta2
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+// Grafana describes a Grafana resource
+// +kubebuilder:resource:path=grafanas,singular=grafana,shortName=graf
+// +kubebuilder:unservedversion
+// +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.deployment.replicas,statuspath=.status.replicas,selectorpath=.status.selector
+// +kubebuilder:printcolumn:name="Image",type=string,JSONPath=".spec.deployment.image"
+// +kubebuilder:printcolumn:name="Replicas",type=integer,JSONPath=".spec.deployment.replicas"
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=".status.url"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+type Grafana struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	meta_v1.TypeMeta   `json:",inline"`
+	meta_v1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +optional
+	Spec   GrafanaSpec   `json:"spec"`
+	Status GrafanaStatus `json:"status,omitempty"`
+}
+
+// GrafanaSpec is the spec for a grafana resource. More than one replica needs an external
+// database.
+type GrafanaSpec struct {
+	// Deployment describes the grafana pods
+	Deployment GrafanaDeployment `json:"deployment,omitempty"`
+
+	// Security selects the admin credentials, generated when unset
+	Security GrafanaSecurity `json:"security,omitempty"`
+
+	// Datasources are the datasources provisioned into grafana
+	Datasources GrafanaDatasources `json:"datasources,omitempty"`
+
+	// DashboardBundles selects the built-in dashboard bundles to mount. When unset the
+	// kafka and zookeeper bundles are mounted, an empty list mounts none.
+	// +kubebuilder:validation:items:Enum=kafka;zookeeper;rabbitmq;burrow
+	DashboardBundles []string `json:"dashboardBundles,omitempty"`
+
+	// Config overrides grafana.ini keys by section. Credentials such as the admin
+	// password are rejected here and must be set through secrets.
+	Config map[string]map[string]string `json:"config,omitempty"`
+
+	// Storage persists /var/lib/grafana in a PersistentVolumeClaim instead of an EmptyDir
+	Storage *GrafanaStorage `json:"storage,omitempty"`
+
+	// Database moves grafana state to an external database, required for more than one replica
+	Database *GrafanaDatabase `json:"database,omitempty"`
+
+	// Service customizes the Service exposing the instance
+	Service *GrafanaService `json:"service,omitempty"`
+
+	// Ingress exposes the instance outside of the cluster, through a Route on OpenShift
+	Ingress *GrafanaIngress `json:"ingress,omitempty"`
+}
+
+// GrafanaDeployment describes the Deployment running grafana
+type GrafanaDeployment struct {
+	// Image defaults to the image of the operator configuration, and is pinned to a digest
+	// on admission when the operator is configured to
+	Image string `json:"image,omitempty"`
+
+	// Replicas defaults to the operator configuration
+	// +kubebuilder:validation:Minimum=1
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Resources of the grafana container
+	Resources v1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// GrafanaSecurity selects the admin credentials of grafana. The inline password of v1 has no
+// field here, it is kept in the v1-password annotation when a v1 resource is read as v1beta2.
+type GrafanaSecurity struct {
+	// AdminUser defaults to the operator configuration unless CredentialsSecretRef is set
+	AdminUser string `json:"adminUser,omitempty"`
+
+	// AdminPassword is the inline password of v1 resources, carried so that they convert
+	// without loss.
+	// Deprecated: store the password in the secret referenced by CredentialsSecretRef.
+	AdminPassword string `json:"adminPassword,omitempty"`
+
+	// CredentialsSecretRef selects the admin credentials, the operator generates them when
+	// unset
+	CredentialsSecretRef *CredentialsSecretRef `json:"credentialsSecretRef,omitempty"`
+}
+
+// GrafanaDatasources describes the datasources provisioned into grafana
+type GrafanaDatasources struct {
+	// PrometheusURL provisions a prometheus datasource, defaults to the operator configuration
+	PrometheusURL string `json:"prometheusURL,omitempty"`
+
+	// Inline datasources are provisioned alongside the prometheus one
+	Inline []GrafanaDatasource `json:"inline,omitempty"`
+
+	// Selector selects GrafanaDataSource resources to provision, from the instance namespace
+	// and from the namespaces the datasources allow through allowedNamespaces
+	Selector *meta_v1.LabelSelector `json:"selector,omitempty"`
+}
+
+// GrafanaIngress describes the external endpoint of an instance
+type GrafanaIngress struct {
+	Host string `json:"host"`
+
+	// Path defaults to /, grafana is configured to serve from any other path
+	Path string `json:"path,omitempty"`
+
+	// TLSSecretName names a kubernetes.io/tls secret, enabling https on the endpoint
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// GrafanaService describes the Service the operator creates for an instance
+type GrafanaService struct {
+	// Type defaults to ClusterIP
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	Type v1.ServiceType `json:"type,omitempty"`
+
+	// Port defaults to the grafana port 3000
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port     int32 `json:"port,omitempty"`
+	NodePort int32 `json:"nodePort,omitempty"`
+
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// +kubebuilder:validation:Enum=None;ClientIP
+	SessionAffinity v1.ServiceAffinity `json:"sessionAffinity,omitempty"`
+}
+
+// GrafanaDatabase describes the external database shared by the replicas of an instance
+type GrafanaDatabase struct {
+	Type DatabaseType `json:"type"`
+
+	// Host is the address of the database as host:port
+	Host string `json:"host"`
+	Name string `json:"name"`
+	User string `json:"user"`
+
+	// PasswordSecretRef selects the password of User in a secret of the instance namespace
+	PasswordSecretRef *v1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+
+	// SSLMode is passed to postgres as ssl_mode, e.g. disable, require or verify-full
+	SSLMode string `json:"sslMode,omitempty"`
+}
+
+// DatabaseType is an external database supported by grafana
+// +kubebuilder:validation:Enum=postgres;mysql
+type DatabaseType string
+
+// These are the supported external databases
+const (
+	DatabaseTypePostgres DatabaseType = "postgres"
+	DatabaseTypeMySQL    DatabaseType = "mysql"
+)
+
+// GrafanaStorage describes the claim holding the grafana data directory
+type GrafanaStorage struct {
+	Size             resource.Quantity `json:"size,omitempty"`
+	StorageClassName *string           `json:"storageClassName,omitempty"`
+
+	// AccessModes of the claim, ReadWriteOnce when empty. They also decide the deployment
+	// strategy when ExistingClaim is set.
+	// +kubebuilder:validation:items:Enum=ReadWriteOnce;ReadOnlyMany;ReadWriteMany
+	AccessModes []v1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+
+	// ExistingClaim mounts a claim managed outside of the operator instead of creating one
+	ExistingClaim string `json:"existingClaim,omitempty"`
+
+	// RetainPolicy decides whether the created claim is deleted along with the Grafana resource
+	RetainPolicy StorageRetainPolicy `json:"retainPolicy,omitempty"`
+}
+
+// StorageRetainPolicy decides what happens to the data claim when the Grafana resource is deleted
+// +kubebuilder:validation:Enum=Delete;Retain
+type StorageRetainPolicy string
+
+// These are the supported retain policies, Delete being the default
+const (
+	StorageRetainPolicyDelete StorageRetainPolicy = "Delete"
+	StorageRetainPolicyRetain StorageRetainPolicy = "Retain"
+)
+
+// GrafanaDatasource describes a datasource provisioned into grafana
+type GrafanaDatasource struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	URL  string `json:"url,omitempty"`
+
+	// +kubebuilder:validation:Enum=proxy;direct
+	Access         string                `json:"access,omitempty"`
+	IsDefault      bool                  `json:"isDefault,omitempty"`
+	JSONData       *runtime.RawExtension `json:"jsonData,omitempty"`
+	SecureJSONData map[string]string     `json:"secureJsonData,omitempty"`
+
+	// SecureJSONDataFrom sources secureJsonData values from secrets in the instance namespace
+	SecureJSONDataFrom map[string]DatasourceValueSource `json:"secureJsonDataFrom,omitempty"`
+}
+
+// DatasourceValueSource selects the source of a datasource value
+type DatasourceValueSource struct {
+	SecretKeyRef *v1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+}
+
+// CredentialsSecretRef selects the secret and keys holding the grafana admin credentials
+type CredentialsSecretRef struct {
+	Name string `json:"name"`
+	// +kubebuilder:default=user
+	UserKey string `json:"userKey,omitempty"`
+
+	// +kubebuilder:default=password
+	PasswordKey string `json:"passwordKey,omitempty"`
+}
+
+// GrafanaStatus defines the observed state of grafana custom resource
+type GrafanaStatus struct {
+	// GStatus mirrors the status of the Ready condition
+	GStatus v1.ConditionStatus `json:"gStatus,omitempty"`
+
+	// LastUpdatedTime is when the operator last changed the status
+	LastUpdatedTime meta_v1.Time       `json:"lastUpdatedTime,omitempty"`
+	Conditions      []GrafanaCondition `json:"conditions,omitempty"`
+
+	// ObservedGeneration is the generation of the spec the status was computed from
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// AdminSecret names the secret holding operator generated admin credentials
+	AdminSecret string `json:"adminSecret,omitempty"`
+
+	// AdminPasswordRotation is the last rotate-admin-password annotation value that was processed
+	AdminPasswordRotation string `json:"adminPasswordRotation,omitempty"`
+
+	// DataSources lists the namespace/name of the GrafanaDataSource resources provisioned
+	DataSources []string `json:"dataSources,omitempty"`
+
+	// URL is the external url of the instance when spec.ingress is set
+	URL string `json:"url,omitempty"`
+
+	// ConfigHash digests the configuration the pods run with, see the config-hash annotation
+	ConfigHash string `json:"configHash,omitempty"`
+
+	// LastRolloutTime is when the pod template of the deployment was last changed
+	LastRolloutTime *meta_v1.Time `json:"lastRolloutTime,omitempty"`
+
+	// Replicas is the number of pods of the deployment, read by the scale subresource
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// Selector selects the pods of the instance, in the string form the scale subresource
+	// and HorizontalPodAutoscalers expect
+	Selector string `json:"selector,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+
+// GrafanaList is a list of Grafana resources
+type GrafanaList struct {
+	meta_v1.TypeMeta `json:",inline"`
+	meta_v1.ListMeta `json:"metadata,omitempty"`
+	Items            []Grafana `json:"items"`
+}
+
+// ConditionType we track, the types and their values are the ones of v1
+type ConditionType string
+
+// ConditionStatus of a condition, True, False or Unknown
+type ConditionStatus string
+
+// ConditionReason is the machine readable reason of a condition
+type ConditionReason string
+
+// GrafanaCondition defines the observed state of grafana custom resource
+type GrafanaCondition struct {
+	Type               ConditionType   `json:"type"`
+	Status             ConditionStatus `json:"status"`
+	Reason             ConditionReason `json:"reason"`
+	Message            string          `json:"message,omitempty"`
+	LastTransitionTime meta_v1.Time    `json:"lastTransitionTime,omitempty"`
+}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 
 	aimsv1 "github.com/dichque/grafana-operator/pkg/client/clientset/versioned/typed/grafana/v1"
+	aimsv1beta2 "github.com/dichque/grafana-operator/pkg/client/clientset/versioned/typed/grafana/v1beta2"
 	discovery "k8s.io/client-go/discovery"
 	rest "k8s.io/client-go/rest"
 	flowcontrol "k8s.io/client-go/util/flowcontrol"
@@ -30,13 +31,15 @@ import (
 type Interface interface {
 	Discovery() discovery.DiscoveryInterface
 	AimsV1() aimsv1.AimsV1Interface
+	AimsV1beta2() aimsv1beta2.AimsV1beta2Interface
 }
 
 // Clientset contains the clients for groups. Each group has exactly one
 // version included in a Clientset.
 type Clientset struct {
 	*discovery.DiscoveryClient
-	aimsV1 *aimsv1.AimsV1Client
+	aimsV1      *aimsv1.AimsV1Client
+	aimsV1beta2 *aimsv1beta2.AimsV1beta2Client
 }
 
 // AimsV1 retrieves the AimsV1Client
@@ -44,6 +47,11 @@ func (c *Clientset) AimsV1() aimsv1.AimsV1Interface {
 	return c.aimsV1
 }
 
+// AimsV1beta2 retrieves the AimsV1beta2Client
+func (c *Clientset) AimsV1beta2() aimsv1beta2.AimsV1beta2Interface {
+	return c.aimsV1beta2
+}
+
 // Discovery retrieves the DiscoveryClient
 func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 	if c == nil {
@@ -69,6 +77,10 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 	if err != nil {
 		return nil, err
 	}
+	cs.aimsV1beta2, err = aimsv1beta2.NewForConfig(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
 
 	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfig(&configShallowCopy)
 	if err != nil {
@@ -82,6 +94,7 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 func NewForConfigOrDie(c *rest.Config) *Clientset {
 	var cs Clientset
 	cs.aimsV1 = aimsv1.NewForConfigOrDie(c)
+	cs.aimsV1beta2 = aimsv1beta2.NewForConfigOrDie(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClientForConfigOrDie(c)
 	return &cs
@@ -91,6 +104,7 @@ func NewForConfigOrDie(c *rest.Config) *Clientset {
 func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.aimsV1 = aimsv1.New(c)
+	cs.aimsV1beta2 = aimsv1beta2.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
 	return &cs
@@ -22,6 +22,8 @@ import (
 	clientset "github.com/dichque/grafana-operator/pkg/client/clientset/versioned"
 	aimsv1 "github.com/dichque/grafana-operator/pkg/client/clientset/versioned/typed/grafana/v1"
 	fakeaimsv1 "github.com/dichque/grafana-operator/pkg/client/clientset/versioned/typed/grafana/v1/fake"
+	aimsv1beta2 "github.com/dichque/grafana-operator/pkg/client/clientset/versioned/typed/grafana/v1beta2"
+	fakeaimsv1beta2 "github.com/dichque/grafana-operator/pkg/client/clientset/versioned/typed/grafana/v1beta2/fake"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
@@ -80,3 +82,8 @@ var _ clientset.Interface = &Clientset{}
 func (c *Clientset) AimsV1() aimsv1.AimsV1Interface {
 	return &fakeaimsv1.FakeAimsV1{Fake: &c.Fake}
 }
+
+// AimsV1beta2 retrieves the AimsV1beta2Client
+func (c *Clientset) AimsV1beta2() aimsv1beta2.AimsV1beta2Interface {
+	return &fakeaimsv1beta2.FakeAimsV1beta2{Fake: &c.Fake}
+}
@@ -20,6 +20,7 @@ package fake
 
 import (
 	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+	aimsv1beta2 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1beta2"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
@@ -32,6 +33,7 @@ var codecs = serializer.NewCodecFactory(scheme)
 var parameterCodec = runtime.NewParameterCodec(scheme)
 var localSchemeBuilder = runtime.SchemeBuilder{
 	aimsv1.AddToScheme,
+	aimsv1beta2.AddToScheme,
 }
 
 // AddToScheme adds all types of this clientset into the given scheme. This allows composition
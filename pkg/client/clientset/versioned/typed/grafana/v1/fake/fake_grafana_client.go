@@ -32,6 +32,18 @@ func (c *FakeAimsV1) Grafanas(namespace string) v1.GrafanaInterface {
 	return &FakeGrafanas{c, namespace}
 }
 
+func (c *FakeAimsV1) GrafanaDashboards(namespace string) v1.GrafanaDashboardInterface {
+	return &FakeGrafanaDashboards{c, namespace}
+}
+
+func (c *FakeAimsV1) GrafanaDataSources(namespace string) v1.GrafanaDataSourceInterface {
+	return &FakeGrafanaDataSources{c, namespace}
+}
+
+func (c *FakeAimsV1) GrafanaFolders(namespace string) v1.GrafanaFolderInterface {
+	return &FakeGrafanaFolders{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeAimsV1) RESTClient() rest.Interface {
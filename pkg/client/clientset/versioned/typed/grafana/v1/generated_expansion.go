@@ -19,3 +19,9 @@ limitations under the License.
 package v1
 
 type GrafanaExpansion interface{}
+
+type GrafanaDashboardExpansion interface{}
+
+type GrafanaDataSourceExpansion interface{}
+
+type GrafanaFolderExpansion interface{}
@@ -27,6 +27,9 @@ import (
 type AimsV1Interface interface {
 	RESTClient() rest.Interface
 	GrafanasGetter
+	GrafanaDashboardsGetter
+	GrafanaDataSourcesGetter
+	GrafanaFoldersGetter
 }
 
 // AimsV1Client is used to interact with features provided by the aims.cisco.com group.
@@ -38,6 +41,18 @@ func (c *AimsV1Client) Grafanas(namespace string) GrafanaInterface {
 	return newGrafanas(c, namespace)
 }
 
+func (c *AimsV1Client) GrafanaDashboards(namespace string) GrafanaDashboardInterface {
+	return newGrafanaDashboards(c, namespace)
+}
+
+func (c *AimsV1Client) GrafanaDataSources(namespace string) GrafanaDataSourceInterface {
+	return newGrafanaDataSources(c, namespace)
+}
+
+func (c *AimsV1Client) GrafanaFolders(namespace string) GrafanaFolderInterface {
+	return newGrafanaFolders(c, namespace)
+}
+
 // NewForConfig creates a new AimsV1Client for the given config.
 func NewForConfig(c *rest.Config) (*AimsV1Client, error) {
 	config := *c
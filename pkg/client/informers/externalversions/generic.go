@@ -22,6 +22,7 @@ import (
 	"fmt"
 
 	v1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+	v1beta2 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1beta2"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
 )
@@ -55,6 +56,16 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	// Group=aims.cisco.com, Version=v1
 	case v1.SchemeGroupVersion.WithResource("grafanas"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Aims().V1().Grafanas().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("grafanadashboards"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Aims().V1().GrafanaDashboards().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("grafanadatasources"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Aims().V1().GrafanaDataSources().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("grafanafolders"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Aims().V1().GrafanaFolders().Informer()}, nil
+
+		// Group=aims.cisco.com, Version=v1beta2
+	case v1beta2.SchemeGroupVersion.WithResource("grafanas"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Aims().V1beta2().Grafanas().Informer()}, nil
 
 	}
 
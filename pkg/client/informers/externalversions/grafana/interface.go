@@ -20,6 +20,7 @@ package grafana
 
 import (
 	v1 "github.com/dichque/grafana-operator/pkg/client/informers/externalversions/grafana/v1"
+	v1beta2 "github.com/dichque/grafana-operator/pkg/client/informers/externalversions/grafana/v1beta2"
 	internalinterfaces "github.com/dichque/grafana-operator/pkg/client/informers/externalversions/internalinterfaces"
 )
 
@@ -27,6 +28,8 @@ import (
 type Interface interface {
 	// V1 provides access to shared informers for resources in V1.
 	V1() v1.Interface
+	// V1beta2 provides access to shared informers for resources in V1beta2.
+	V1beta2() v1beta2.Interface
 }
 
 type group struct {
@@ -44,3 +47,8 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 func (g *group) V1() v1.Interface {
 	return v1.New(g.factory, g.namespace, g.tweakListOptions)
 }
+
+// V1beta2 returns a new v1beta2.Interface.
+func (g *group) V1beta2() v1beta2.Interface {
+	return v1beta2.New(g.factory, g.namespace, g.tweakListOptions)
+}
@@ -26,6 +26,12 @@ import (
 type Interface interface {
 	// Grafanas returns a GrafanaInformer.
 	Grafanas() GrafanaInformer
+	// GrafanaDashboards returns a GrafanaDashboardInformer.
+	GrafanaDashboards() GrafanaDashboardInformer
+	// GrafanaDataSources returns a GrafanaDataSourceInformer.
+	GrafanaDataSources() GrafanaDataSourceInformer
+	// GrafanaFolders returns a GrafanaFolderInformer.
+	GrafanaFolders() GrafanaFolderInformer
 }
 
 type version struct {
@@ -43,3 +49,18 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 func (v *version) Grafanas() GrafanaInformer {
 	return &grafanaInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// GrafanaDashboards returns a GrafanaDashboardInformer.
+func (v *version) GrafanaDashboards() GrafanaDashboardInformer {
+	return &grafanaDashboardInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// GrafanaDataSources returns a GrafanaDataSourceInformer.
+func (v *version) GrafanaDataSources() GrafanaDataSourceInformer {
+	return &grafanaDataSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// GrafanaFolders returns a GrafanaFolderInformer.
+func (v *version) GrafanaFolders() GrafanaFolderInformer {
+	return &grafanaFolderInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -0,0 +1,58 @@
+// Package conditions maintains the conditions listed in the status of Grafana resources
+package conditions
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+)
+
+// Find returns the condition of the given type, nil if there is none
+func Find(conditions []aimsv1.GrafanaCondition, conditionType aimsv1.ConditionType) *aimsv1.GrafanaCondition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+// IsTrue reports whether the condition of the given type is present with status True
+func IsTrue(conditions []aimsv1.GrafanaCondition, conditionType aimsv1.ConditionType) bool {
+	condition := Find(conditions, conditionType)
+	return condition != nil && condition.Status == aimsv1.ConditionStatusTrue
+}
+
+// Set adds condition or replaces the condition of the same type and reports whether anything
+// changed. The transition time is set when the status changes and kept otherwise, so that
+// setting the same condition on every reconcile leaves the status untouched.
+func Set(conditions *[]aimsv1.GrafanaCondition, condition aimsv1.GrafanaCondition) bool {
+	current := Find(*conditions, condition.Type)
+	if current == nil {
+		condition.LastTransitionTime = metav1.Now()
+		*conditions = append(*conditions, condition)
+		return true
+	}
+
+	if current.Status == condition.Status {
+		condition.LastTransitionTime = current.LastTransitionTime
+	} else {
+		condition.LastTransitionTime = metav1.Now()
+	}
+	if *current == condition {
+		return false
+	}
+	*current = condition
+	return true
+}
+
+// Remove deletes the condition of the given type and reports whether it was present
+func Remove(conditions *[]aimsv1.GrafanaCondition, conditionType aimsv1.ConditionType) bool {
+	for i := range *conditions {
+		if (*conditions)[i].Type == conditionType {
+			*conditions = append((*conditions)[:i], (*conditions)[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package conditions
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+)
+
+func TestSet(t *testing.T) {
+	past := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	ready := aimsv1.GrafanaCondition{
+		Type:               aimsv1.ConditionTypeReady,
+		Status:             aimsv1.ConditionStatusTrue,
+		Reason:             aimsv1.ConditionReasonReplicasAvailable,
+		Message:            "1 of 1 replicas available",
+		LastTransitionTime: past,
+	}
+
+	tests := []struct {
+		name       string
+		conditions []aimsv1.GrafanaCondition
+		condition  aimsv1.GrafanaCondition
+		changed    bool
+		transition bool
+	}{
+		{
+			name:       "added",
+			condition:  aimsv1.GrafanaCondition{Type: aimsv1.ConditionTypeReady, Status: aimsv1.ConditionStatusFalse},
+			changed:    true,
+			transition: true,
+		},
+		{
+			name:       "unchanged",
+			conditions: []aimsv1.GrafanaCondition{ready},
+			condition:  aimsv1.GrafanaCondition{Type: ready.Type, Status: ready.Status, Reason: ready.Reason, Message: ready.Message},
+		},
+		{
+			name:       "message changed",
+			conditions: []aimsv1.GrafanaCondition{ready},
+			condition:  aimsv1.GrafanaCondition{Type: ready.Type, Status: ready.Status, Reason: ready.Reason, Message: "2 of 2 replicas available"},
+			changed:    true,
+		},
+		{
+			name:       "status changed",
+			conditions: []aimsv1.GrafanaCondition{ready},
+			condition:  aimsv1.GrafanaCondition{Type: ready.Type, Status: aimsv1.ConditionStatusFalse, Reason: aimsv1.ConditionReasonReplicasUnavailable},
+			changed:    true,
+			transition: true,
+		},
+		{
+			name:       "other type",
+			conditions: []aimsv1.GrafanaCondition{ready},
+			condition:  aimsv1.GrafanaCondition{Type: aimsv1.ConditionTypeStalled, Status: aimsv1.ConditionStatusFalse},
+			changed:    true,
+			transition: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conditions := append([]aimsv1.GrafanaCondition{}, test.conditions...)
+			if changed := Set(&conditions, test.condition); changed != test.changed {
+				t.Errorf("expected changed %v, got %v", test.changed, changed)
+			}
+
+			set := Find(conditions, test.condition.Type)
+			if set == nil {
+				t.Fatalf("condition %s not set: %v", test.condition.Type, conditions)
+			}
+			if set.Status != test.condition.Status || set.Reason != test.condition.Reason || set.Message != test.condition.Message {
+				t.Errorf("expected %v, got %v", test.condition, *set)
+			}
+			if transitioned := !set.LastTransitionTime.Equal(&past); transitioned != test.transition {
+				t.Errorf("expected transition %v, got last transition at %s", test.transition, set.LastTransitionTime)
+			}
+			if len(conditions) != len(test.conditions) && Find(test.conditions, test.condition.Type) != nil {
+				t.Errorf("condition duplicated: %v", conditions)
+			}
+		})
+	}
+}
+
+func TestRemove(t *testing.T) {
+	tests := []struct {
+		name          string
+		conditions    []aimsv1.ConditionType
+		conditionType aimsv1.ConditionType
+		removed       bool
+		remaining     []aimsv1.ConditionType
+	}{
+		{name: "empty", conditionType: aimsv1.ConditionTypeConflict, remaining: []aimsv1.ConditionType{}},
+		{
+			name:          "absent",
+			conditions:    []aimsv1.ConditionType{aimsv1.ConditionTypeReady},
+			conditionType: aimsv1.ConditionTypeConflict,
+			remaining:     []aimsv1.ConditionType{aimsv1.ConditionTypeReady},
+		},
+		{
+			name:          "present",
+			conditions:    []aimsv1.ConditionType{aimsv1.ConditionTypeReady, aimsv1.ConditionTypeConflict, aimsv1.ConditionTypeStalled},
+			conditionType: aimsv1.ConditionTypeConflict,
+			removed:       true,
+			remaining:     []aimsv1.ConditionType{aimsv1.ConditionTypeReady, aimsv1.ConditionTypeStalled},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conditions := []aimsv1.GrafanaCondition{}
+			for _, conditionType := range test.conditions {
+				conditions = append(conditions, aimsv1.GrafanaCondition{Type: conditionType, Status: aimsv1.ConditionStatusTrue})
+			}
+			if removed := Remove(&conditions, test.conditionType); removed != test.removed {
+				t.Errorf("expected removed %v, got %v", test.removed, removed)
+			}
+			remaining := []aimsv1.ConditionType{}
+			for _, condition := range conditions {
+				remaining = append(remaining, condition.Type)
+			}
+			if len(remaining) != len(test.remaining) {
+				t.Fatalf("expected %v, got %v", test.remaining, remaining)
+			}
+			for i := range remaining {
+				if remaining[i] != test.remaining[i] {
+					t.Errorf("expected %v, got %v", test.remaining, remaining)
+				}
+			}
+		})
+	}
+}
@@ -3,10 +3,25 @@ package config
 const (
 	TemplatePath  string = "config/templates/"
 	DashboardPath string = "config/templates/dashboards/"
+
+	// DefaultINIPath holds the operator defaults spec.config is merged over
+	DefaultINIPath string = "config/templates/grafana.defaults.ini"
 )
 
 type GrafanaConfig struct {
-	AdminUser     string
-	AdminPassword string
-	PrometheusURL string
+	Datasources []Datasource
+}
+
+// Datasource is a datasource entry of the grafana provisioning file
+type Datasource struct {
+	Name           string
+	Type           string
+	URL            string
+	Access         string
+	IsDefault      bool
+	JSONData       string
+	SecureJSONData map[string]string
+
+	// Secure datasources are provisioned from a secret rather than a configmap
+	Secure bool
 }
@@ -0,0 +1,177 @@
+package crd
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog"
+)
+
+// What the operator does with its CustomResourceDefinitions at startup
+const (
+	// ModeInstall creates the CRDs or updates them to the generated ones
+	ModeInstall string = "install"
+	// ModeValidate fails when an installed CRD differs from the generated one
+	ModeValidate string = "validate"
+	// ModeNone leaves the CRDs alone
+	ModeNone string = "none"
+)
+
+// GVR is the resource of CustomResourceDefinitions
+var GVR = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// Objects returns the CustomResourceDefinitions generated from the API types
+func Objects() ([]*unstructured.Unstructured, error) {
+	objects := []*unstructured.Unstructured{}
+	for _, data := range generated {
+		obj := map[string]interface{}{}
+		// The apimachinery decoder keeps integers int64 like the dynamic client does
+		if err := json.Unmarshal([]byte(data), &obj); err != nil {
+			return nil, err
+		}
+		objects = append(objects, &unstructured.Unstructured{Object: obj})
+	}
+	return objects, nil
+}
+
+// Ensure installs or validates the CustomResourceDefinitions according to mode
+func Ensure(client dynamic.Interface, mode string) error {
+	switch mode {
+	case ModeNone:
+		return nil
+	case ModeInstall, ModeValidate:
+	default:
+		return fmt.Errorf("unknown crd mode %q, expected one of %s, %s or %s", mode, ModeInstall, ModeValidate, ModeNone)
+	}
+
+	desired, err := Objects()
+	if err != nil {
+		return err
+	}
+	crds := client.Resource(GVR)
+	for _, crd := range desired {
+		found, err := crds.Get(crd.GetName(), metav1.GetOptions{})
+		if err != nil && errors.IsNotFound(err) {
+			if mode == ModeValidate {
+				return fmt.Errorf("crd %s is not installed", crd.GetName())
+			}
+			if _, err = crds.Create(crd, metav1.CreateOptions{}); err != nil {
+				return err
+			}
+			klog.Infof("crd created: %s", crd.GetName())
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		if diff := Diff(found, crd); diff != "" {
+			if mode == ModeValidate {
+				group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+				plural, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "plural")
+				return fmt.Errorf("crd %s differs from the one generated from the API types in %s, install config/crd/%s_%s.yaml or run with -crd=install", crd.GetName(), diff, group, plural)
+			}
+			updated := found.DeepCopy()
+			updated.Object["spec"] = mergeSpec(found, crd)
+			if _, err = crds.Update(updated, metav1.UpdateOptions{}); err != nil {
+				return err
+			}
+			klog.Infof("crd updated: %s, it differed in %s", crd.GetName(), diff)
+		}
+	}
+	return nil
+}
+
+// Diff names the first part of the installed CRD that differs from the desired one, empty
+// when they match. Fields defaulted by the API server are not compared.
+func Diff(found, desired *unstructured.Unstructured) string {
+	for _, field := range []string{"group", "names", "scope"} {
+		want, _, _ := unstructured.NestedFieldNoCopy(desired.Object, "spec", field)
+		got, _, _ := unstructured.NestedFieldNoCopy(found.Object, "spec", field)
+		if field == "names" {
+			// listKind is defaulted
+			got = withoutKey(got, "listKind")
+			want = withoutKey(want, "listKind")
+		}
+		if !equality.Semantic.DeepEqual(got, want) {
+			return "spec." + field
+		}
+	}
+
+	wantVersions, _, _ := unstructured.NestedSlice(desired.Object, "spec", "versions")
+	gotVersions, _, _ := unstructured.NestedSlice(found.Object, "spec", "versions")
+	if len(wantVersions) != len(gotVersions) {
+		return "spec.versions"
+	}
+	webhook := convertsWithWebhook(found)
+	for i := range wantVersions {
+		want, _ := wantVersions[i].(map[string]interface{})
+		got, _ := gotVersions[i].(map[string]interface{})
+		for _, field := range []string{"name", "served", "storage", "schema", "subresources", "additionalPrinterColumns"} {
+			if field == "served" && webhook && got[field] == true {
+				// Served by the conversion webhook, see webhook.CertManager
+				continue
+			}
+			if !equality.Semantic.DeepEqual(got[field], want[field]) {
+				return fmt.Sprintf("spec.versions[%d].%s", i, field)
+			}
+		}
+	}
+	return ""
+}
+
+// mergeSpec returns the desired spec, keeping the conversion settings of the installed CRD
+// when the desired one has none as the operator patches their caBundle at runtime. Versions
+// served through the conversion webhook stay served.
+func mergeSpec(found, desired *unstructured.Unstructured) map[string]interface{} {
+	spec, _, _ := unstructured.NestedMap(desired.Object, "spec")
+	if _, ok := spec["conversion"]; ok {
+		return spec
+	}
+	if conversion, ok, _ := unstructured.NestedMap(found.Object, "spec", "conversion"); ok {
+		spec["conversion"] = conversion
+	}
+	if !convertsWithWebhook(found) {
+		return spec
+	}
+
+	served := map[interface{}]bool{}
+	gotVersions, _, _ := unstructured.NestedSlice(found.Object, "spec", "versions")
+	for _, version := range gotVersions {
+		if version, ok := version.(map[string]interface{}); ok && version["served"] == true {
+			served[version["name"]] = true
+		}
+	}
+	versions, _ := spec["versions"].([]interface{})
+	for _, version := range versions {
+		if version, ok := version.(map[string]interface{}); ok && served[version["name"]] {
+			version["served"] = true
+		}
+	}
+	return spec
+}
+
+// convertsWithWebhook reports whether the installed CRD converts its versions with a webhook
+func convertsWithWebhook(found *unstructured.Unstructured) bool {
+	strategy, _, _ := unstructured.NestedString(found.Object, "spec", "conversion", "strategy")
+	return strategy == "Webhook"
+}
+
+func withoutKey(value interface{}, key string) interface{} {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+	copied := map[string]interface{}{}
+	for k, v := range m {
+		if k != key {
+			copied[k] = v
+		}
+	}
+	return copied
+}
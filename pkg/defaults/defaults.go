@@ -0,0 +1,126 @@
+// Package defaults fills the unset fields of Grafana resources from the operator configuration.
+// Only the defaulting webhook applies them, so that the controller reconciles what is persisted,
+// except for the image the controller falls back to when the webhook is not installed.
+package defaults
+
+import (
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+)
+
+// AppliedAnnotation lists the defaults the operator filled into a Grafana resource
+const AppliedAnnotation string = "aims.cisco.com/defaults-applied"
+
+// Names of the defaults recorded in AppliedAnnotation
+const (
+	Image         string = "image"
+	ImageDigest   string = "image-digest"
+	Replicas      string = "replicas"
+	User          string = "user"
+	PrometheusURL string = "prometheus_url"
+)
+
+// Defaults are the values of the unset fields of Grafana resources. An empty password is not
+// defaulted, the operator generates one into the admin secret instead.
+type Defaults struct {
+	Image         string `json:"image,omitempty"`
+	Replicas      int32  `json:"replicas,omitempty"`
+	User          string `json:"user,omitempty"`
+	PrometheusURL string `json:"prometheusURL,omitempty"`
+
+	// PinDigests resolves image tags to the digest they point to when a resource is admitted,
+	// so that its pods keep running the same image when the tag moves. The registry is queried
+	// anonymously, images of private registries are pinned with Digests instead.
+	PinDigests bool `json:"pinDigests,omitempty"`
+
+	// Digests pins images to fixed digests, by image reference. They take precedence over the
+	// registry and allow pinning in clusters without registry access.
+	Digests map[string]string `json:"digests,omitempty"`
+}
+
+// Builtin returns the defaults used without operator configuration
+func Builtin() *Defaults {
+	return &Defaults{
+		Image:         "containers.cisco.com/intps/grafana:latest",
+		Replicas:      1,
+		User:          "admin",
+		PrometheusURL: "http://prometheus-operated:9090",
+	}
+}
+
+// Load reads the defaults from a YAML file, falling back to the builtin defaults for the values
+// it leaves unset. An empty path returns the builtin defaults.
+func Load(path string) (*Defaults, error) {
+	d := Builtin()
+	if path == "" {
+		return d, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err = yaml.UnmarshalStrict(data, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Apply fills the unset fields of grafana and returns the names of the defaults it applied
+func (d *Defaults) Apply(grafana *aimsv1.Grafana) []string {
+	applied := []string{}
+	spec := &grafana.Spec
+	if spec.Image == "" && d.Image != "" {
+		spec.Image = d.Image
+		applied = append(applied, Image)
+	}
+	if spec.Replicas == nil && d.Replicas > 0 {
+		replicas := d.Replicas
+		spec.Replicas = &replicas
+		applied = append(applied, Replicas)
+	}
+	// The user of a credentials secret comes from the secret
+	if spec.Username == "" && spec.CredentialsSecretRef == nil && d.User != "" {
+		spec.Username = d.User
+		applied = append(applied, User)
+	}
+	if spec.PrometheusURL == "" && d.PrometheusURL != "" {
+		spec.PrometheusURL = d.PrometheusURL
+		applied = append(applied, PrometheusURL)
+	}
+	return applied
+}
+
+// Record adds the names of applied defaults to the AppliedAnnotation of grafana and reports
+// whether the annotation changed
+func Record(grafana *aimsv1.Grafana, applied []string) bool {
+	names := map[string]bool{}
+	current := grafana.Annotations[AppliedAnnotation]
+	if current != "" {
+		for _, name := range strings.Split(current, ",") {
+			names[name] = true
+		}
+	}
+	for _, name := range applied {
+		names[name] = true
+	}
+
+	list := []string{}
+	for name := range names {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	value := strings.Join(list, ",")
+	if value == current {
+		return false
+	}
+	if grafana.Annotations == nil {
+		grafana.Annotations = map[string]string{}
+	}
+	grafana.Annotations[AppliedAnnotation] = value
+	return true
+}
@@ -0,0 +1,268 @@
+package defaults
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	dockerHub         string = "docker.io"
+	dockerHubRegistry string = "registry-1.docker.io"
+
+	digestHeader string = "Docker-Content-Digest"
+
+	// resolved digests are cached so that repeated admissions do not hit the registry
+	digestCacheTTL  = 10 * time.Minute
+	registryTimeout = 5 * time.Second
+	// admission waits this long for a digest before going on with the tag
+	admissionTimeout = 500 * time.Millisecond
+)
+
+// manifestTypes are the manifests a tag may point to, multi-arch indexes first so the digest
+// of a multi-arch image stays valid on every node
+var manifestTypes = []string{
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+}
+
+// Pinner resolves image tags to digests, from the configured digests or else from the registry
+// when PinDigests is set. Registry lookups run in the background so that admission never waits
+// on the registry for longer than admissionTimeout.
+type Pinner struct {
+	digests  map[string]string
+	registry bool
+	client   *http.Client
+	timeout  time.Duration
+	resolver func(registry, repository, tag string) (string, error)
+
+	mu      sync.Mutex
+	cache   map[string]cachedDigest
+	pending map[string]chan struct{}
+}
+
+type cachedDigest struct {
+	digest  string
+	err     error
+	expires time.Time
+}
+
+// NewPinner returns a Pinner preferring the digests of d
+func NewPinner(d *Defaults) *Pinner {
+	p := &Pinner{
+		digests:  d.Digests,
+		registry: d.PinDigests,
+		client:   &http.Client{Timeout: registryTimeout},
+		timeout:  admissionTimeout,
+		cache:    map[string]cachedDigest{},
+		pending:  map[string]chan struct{}{},
+	}
+	p.resolver = p.resolve
+	return p
+}
+
+// Pin returns image with the digest its tag points to appended, image itself when it already
+// carries a digest or pinning is not configured for it. A digest that is neither configured,
+// cached nor resolved within the admission timeout returns an error, the lookup goes on in the
+// background for the next admission.
+func (p *Pinner) Pin(image string) (string, error) {
+	if strings.Contains(image, "@") {
+		return image, nil
+	}
+	if digest, ok := p.digests[image]; ok {
+		return image + "@" + digest, nil
+	}
+	if !p.registry {
+		return image, nil
+	}
+
+	p.mu.Lock()
+	cached, ok := p.cache[image]
+	if ok && time.Now().Before(cached.expires) {
+		p.mu.Unlock()
+		if cached.err != nil {
+			return "", fmt.Errorf("resolving digest of %s: %s", image, cached.err)
+		}
+		return image + "@" + cached.digest, nil
+	}
+	done, ok := p.pending[image]
+	if !ok {
+		done = make(chan struct{})
+		p.pending[image] = done
+		go p.lookup(image, done)
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-done:
+	case <-time.After(p.timeout):
+		return "", fmt.Errorf("digest of %s not resolved within %s", image, p.timeout)
+	}
+
+	p.mu.Lock()
+	cached, ok = p.cache[image]
+	p.mu.Unlock()
+	if cached.err != nil {
+		return "", fmt.Errorf("resolving digest of %s: %s", image, cached.err)
+	}
+	return image + "@" + cached.digest, nil
+}
+
+// lookup resolves the digest of image from the registry into the cache, and closes done.
+// Failures are cached for a short while so that admissions do not hammer an unreachable registry.
+func (p *Pinner) lookup(image string, done chan struct{}) {
+	registry, repository, tag := parseImage(image)
+	digest, err := p.resolver(registry, repository, tag)
+
+	entry := cachedDigest{digest: digest, expires: time.Now().Add(digestCacheTTL)}
+	if err != nil {
+		entry = cachedDigest{err: err, expires: time.Now().Add(registryTimeout)}
+	}
+	p.mu.Lock()
+	p.cache[image] = entry
+	delete(p.pending, image)
+	p.mu.Unlock()
+	close(done)
+}
+
+// parseImage splits an image reference without digest into registry, repository and tag,
+// following the docker conventions for images of Docker Hub
+func parseImage(image string) (registry, repository, tag string) {
+	repository, tag = image, "latest"
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		repository, tag = image[:i], image[i+1:]
+	}
+
+	registry = dockerHub
+	if i := strings.Index(repository, "/"); i >= 0 {
+		host := repository[:i]
+		if strings.ContainsAny(host, ".:") || host == "localhost" {
+			registry, repository = host, repository[i+1:]
+		}
+	}
+	if registry == dockerHub {
+		registry = dockerHubRegistry
+		if !strings.Contains(repository, "/") {
+			repository = "library/" + repository
+		}
+	}
+	return registry, repository, tag
+}
+
+// resolve asks the registry for the digest of a tag, anonymously or with the anonymous bearer
+// token the registry hands out
+func (p *Pinner) resolve(registry, repository, tag string) (string, error) {
+	manifest := fmt.Sprintf("https://%s/v2/%s/manifests/%s", registry, repository, tag)
+	resp, err := p.headManifest(manifest, "")
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		token, err := p.token(resp.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return "", err
+		}
+		if resp, err = p.headManifest(manifest, token); err != nil {
+			return "", err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry answered %s", resp.Status)
+	}
+	digest := resp.Header.Get(digestHeader)
+	if digest == "" {
+		return "", fmt.Errorf("registry returned no %s header", digestHeader)
+	}
+	return digest, nil
+}
+
+func (p *Pinner) headManifest(manifest, token string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodHead, manifest, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestTypes, ", "))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// token fetches an anonymous token from the realm of a Bearer challenge
+func (p *Pinner) token(challenge string) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", fmt.Errorf("unsupported registry authentication %q", challenge)
+	}
+	params := parseChallenge(strings.TrimPrefix(challenge, "Bearer "))
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Host == "" {
+		return "", fmt.Errorf("invalid registry authentication realm %q", params["realm"])
+	}
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			query.Set(key, params[key])
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	resp, err := p.client.Get(realm.String())
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry token request answered %s", resp.Status)
+	}
+	body := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	return body.AccessToken, nil
+}
+
+// parseChallenge parses the key="value" pairs of a WWW-Authenticate challenge
+func parseChallenge(challenge string) map[string]string {
+	params := map[string]string{}
+	for challenge != "" {
+		i := strings.Index(challenge, "=")
+		if i < 0 {
+			break
+		}
+		key := strings.TrimSpace(challenge[:i])
+		challenge = challenge[i+1:]
+
+		var value string
+		if strings.HasPrefix(challenge, `"`) {
+			end := strings.Index(challenge[1:], `"`)
+			if end < 0 {
+				break
+			}
+			value, challenge = challenge[1:end+1], challenge[end+2:]
+		} else if j := strings.Index(challenge, ","); j >= 0 {
+			value, challenge = challenge[:j], challenge[j:]
+		} else {
+			value, challenge = challenge, ""
+		}
+		params[key] = value
+		challenge = strings.TrimPrefix(challenge, ",")
+	}
+	return params
+}
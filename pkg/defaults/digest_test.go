@@ -0,0 +1,196 @@
+package defaults
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseImage(t *testing.T) {
+	tests := []struct {
+		image      string
+		registry   string
+		repository string
+		tag        string
+	}{
+		{image: "grafana", registry: dockerHubRegistry, repository: "library/grafana", tag: "latest"},
+		{image: "grafana:6.7.3", registry: dockerHubRegistry, repository: "library/grafana", tag: "6.7.3"},
+		{image: "grafana/grafana:6.7.3", registry: dockerHubRegistry, repository: "grafana/grafana", tag: "6.7.3"},
+		{image: "docker.io/grafana/grafana:6.7.3", registry: dockerHubRegistry, repository: "grafana/grafana", tag: "6.7.3"},
+		{image: "containers.cisco.com/intps/grafana:latest", registry: "containers.cisco.com", repository: "intps/grafana", tag: "latest"},
+		{image: "registry:5000/grafana", registry: "registry:5000", repository: "grafana", tag: "latest"},
+		{image: "registry:5000/team/grafana:7.0", registry: "registry:5000", repository: "team/grafana", tag: "7.0"},
+		{image: "localhost/grafana:dev", registry: "localhost", repository: "grafana", tag: "dev"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.image, func(t *testing.T) {
+			registry, repository, tag := parseImage(test.image)
+			if registry != test.registry || repository != test.repository || tag != test.tag {
+				t.Errorf("expected %s %s %s, got %s %s %s", test.registry, test.repository, test.tag, registry, repository, tag)
+			}
+		})
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	tests := []struct {
+		name      string
+		challenge string
+		expected  map[string]string
+	}{
+		{
+			name:      "docker hub",
+			challenge: `realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/grafana:pull"`,
+			expected: map[string]string{
+				"realm":   "https://auth.docker.io/token",
+				"service": "registry.docker.io",
+				"scope":   "repository:library/grafana:pull",
+			},
+		},
+		{
+			name:      "unquoted values",
+			challenge: `realm=https://auth.example.com/token, service=registry`,
+			expected:  map[string]string{"realm": "https://auth.example.com/token", "service": "registry"},
+		},
+		{
+			name:      "comma in quoted value",
+			challenge: `realm="https://auth.example.com/token",scope="repository:a:pull,push"`,
+			expected:  map[string]string{"realm": "https://auth.example.com/token", "scope": "repository:a:pull,push"},
+		},
+		{name: "empty", challenge: "", expected: map[string]string{}},
+		{name: "unterminated quote", challenge: `realm="https://auth`, expected: map[string]string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if params := parseChallenge(test.challenge); !reflect.DeepEqual(params, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, params)
+			}
+		})
+	}
+}
+
+func TestPin(t *testing.T) {
+	const digest = "sha256:0123456789abcdef"
+	tests := []struct {
+		name     string
+		defaults Defaults
+		image    string
+		resolver func(registry, repository, tag string) (string, error)
+		expected string
+		failed   bool
+	}{
+		{
+			name:     "digest already set",
+			defaults: Defaults{PinDigests: true},
+			image:    "grafana/grafana@" + digest,
+			expected: "grafana/grafana@" + digest,
+		},
+		{
+			name:     "configured digest",
+			defaults: Defaults{Digests: map[string]string{"grafana/grafana:6.7.3": digest}},
+			image:    "grafana/grafana:6.7.3",
+			expected: "grafana/grafana:6.7.3@" + digest,
+		},
+		{
+			name:     "pinning disabled",
+			image:    "grafana/grafana:6.7.3",
+			expected: "grafana/grafana:6.7.3",
+		},
+		{
+			name:     "resolved",
+			defaults: Defaults{PinDigests: true},
+			image:    "grafana/grafana:6.7.3",
+			resolver: func(string, string, string) (string, error) { return digest, nil },
+			expected: "grafana/grafana:6.7.3@" + digest,
+		},
+		{
+			name:     "registry error",
+			defaults: Defaults{PinDigests: true},
+			image:    "grafana/grafana:6.7.3",
+			resolver: func(string, string, string) (string, error) { return "", errors.New("unreachable") },
+			failed:   true,
+		},
+		{
+			name:     "slow registry",
+			defaults: Defaults{PinDigests: true},
+			image:    "grafana/grafana:6.7.3",
+			resolver: func(string, string, string) (string, error) {
+				time.Sleep(time.Second)
+				return digest, nil
+			},
+			failed: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := NewPinner(&test.defaults)
+			p.timeout = 50 * time.Millisecond
+			p.resolver = func(string, string, string) (string, error) {
+				t.Fatal("unexpected registry lookup")
+				return "", nil
+			}
+			if test.resolver != nil {
+				p.resolver = test.resolver
+			}
+
+			pinned, err := p.Pin(test.image)
+			if test.failed {
+				if err == nil {
+					t.Fatalf("expected an error, got %s", pinned)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if pinned != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, pinned)
+			}
+		})
+	}
+}
+
+// TestPinCaches checks that a digest resolved after the admission timeout serves the next
+// admissions without asking the registry again
+func TestPinCaches(t *testing.T) {
+	const digest = "sha256:0123456789abcdef"
+	lookups := make(chan struct{}, 10)
+	release := make(chan struct{})
+
+	p := NewPinner(&Defaults{PinDigests: true})
+	p.timeout = 10 * time.Millisecond
+	p.resolver = func(string, string, string) (string, error) {
+		lookups <- struct{}{}
+		<-release
+		return digest, nil
+	}
+
+	if _, err := p.Pin("grafana/grafana:6.7.3"); err == nil {
+		t.Fatal("expected the first admission to go on without a digest")
+	}
+	if _, err := p.Pin("grafana/grafana:6.7.3"); err == nil {
+		t.Fatal("expected the second admission to go on without a digest")
+	}
+	close(release)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		pinned, err := p.Pin("grafana/grafana:6.7.3")
+		if err == nil {
+			if pinned != "grafana/grafana:6.7.3@"+digest {
+				t.Errorf("unexpected pinned image %s", pinned)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("digest never cached: %s", err)
+		}
+	}
+	if len(lookups) != 1 {
+		t.Errorf("expected a single registry lookup, got %d", len(lookups))
+	}
+}
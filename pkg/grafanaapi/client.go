@@ -0,0 +1,175 @@
+package grafanaapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const requestTimeout = 10 * time.Second
+
+// Client talks to the HTTP API of a single grafana endpoint using basic auth
+type Client struct {
+	baseURL  string
+	user     string
+	password string
+	http     *http.Client
+}
+
+// User is the subset of the grafana user model the operator needs
+type User struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+}
+
+// Folder is the subset of the grafana folder model the operator needs
+type Folder struct {
+	ID    int64  `json:"id"`
+	UID   string `json:"uid"`
+	Title string `json:"title"`
+}
+
+// Team is the subset of the grafana team model the operator needs
+type Team struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// PermissionItem grants a permission to exactly one of a role, a team or a user
+type PermissionItem struct {
+	Role       string `json:"role,omitempty"`
+	TeamID     int64  `json:"teamId,omitempty"`
+	UserID     int64  `json:"userId,omitempty"`
+	Permission int    `json:"permission"`
+}
+
+// StatusError is returned for responses outside of the 2xx range
+type StatusError struct {
+	Method string
+	Path   string
+	Code   int
+	Body   string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s %s: unexpected status %d: %s", e.Method, e.Path, e.Code, e.Body)
+}
+
+// IsNotFound reports whether err is a 404 response of the grafana API
+func IsNotFound(err error) bool {
+	statusErr, ok := err.(*StatusError)
+	return ok && statusErr.Code == http.StatusNotFound
+}
+
+// NewClient returns a client for the grafana listening at baseURL
+func NewClient(baseURL, user, password string) *Client {
+	return &Client{
+		baseURL:  baseURL,
+		user:     user,
+		password: password,
+		http:     &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// LookupUser returns the user with the given login or email
+func (c *Client) LookupUser(login string) (*User, error) {
+	user := &User{}
+	err := c.do(http.MethodGet, "/api/users/lookup?loginOrEmail="+url.QueryEscape(login), nil, user)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// UpdateUserPassword sets the password of a user through the admin API
+func (c *Client) UpdateUserPassword(id int64, password string) error {
+	body := map[string]string{"password": password}
+	return c.do(http.MethodPut, fmt.Sprintf("/api/admin/users/%d/password", id), body, nil)
+}
+
+// GetFolder returns the folder with the given uid
+func (c *Client) GetFolder(uid string) (*Folder, error) {
+	folder := &Folder{}
+	if err := c.do(http.MethodGet, "/api/folders/"+url.PathEscape(uid), nil, folder); err != nil {
+		return nil, err
+	}
+	return folder, nil
+}
+
+// CreateFolder creates a folder with a fixed uid
+func (c *Client) CreateFolder(uid, title string) (*Folder, error) {
+	folder := &Folder{}
+	body := map[string]string{"uid": uid, "title": title}
+	if err := c.do(http.MethodPost, "/api/folders", body, folder); err != nil {
+		return nil, err
+	}
+	return folder, nil
+}
+
+// UpdateFolderTitle renames a folder, overwriting concurrent changes
+func (c *Client) UpdateFolderTitle(uid, title string) error {
+	body := map[string]interface{}{"title": title, "overwrite": true}
+	return c.do(http.MethodPut, "/api/folders/"+url.PathEscape(uid), body, nil)
+}
+
+// SetFolderPermissions replaces all permissions of a folder
+func (c *Client) SetFolderPermissions(uid string, items []PermissionItem) error {
+	body := map[string]interface{}{"items": items}
+	return c.do(http.MethodPost, "/api/folders/"+url.PathEscape(uid)+"/permissions", body, nil)
+}
+
+// LookupTeam returns the team with the given name
+func (c *Client) LookupTeam(name string) (*Team, error) {
+	result := struct {
+		Teams []Team `json:"teams"`
+	}{}
+	if err := c.do(http.MethodGet, "/api/teams/search?name="+url.QueryEscape(name), nil, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Teams) == 0 {
+		return nil, fmt.Errorf("team %s not found", name)
+	}
+	return &result.Teams[0], nil
+}
+
+func (c *Client) do(method, path string, in, out interface{}) error {
+	var body bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, &body)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.user, c.password)
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &StatusError{Method: method, Path: path, Code: resp.StatusCode, Body: string(data)}
+	}
+
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,159 @@
+// Package ini models grafana.ini style configuration files as ordered sections of
+// ordered keys so configuration layers can be merged and written back out.
+package ini
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+)
+
+// File is a parsed ini file. Comments are not retained.
+type File struct {
+	sections []*Section
+}
+
+// Section is a named group of keys. The unnamed section holds keys set before the first header.
+type Section struct {
+	Name string
+	keys []string
+	vals map[string]string
+}
+
+// New returns an empty ini file
+func New() *File {
+	return &File{}
+}
+
+// Parse reads an ini file. Blank lines and lines starting with # or ; are skipped.
+func Parse(data string) (*File, error) {
+	f := New()
+	section := ""
+
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: unterminated section header", n)
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			f.section(section, true)
+			continue
+		}
+
+		i := strings.Index(line, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("line %d: expected key = value", n)
+		}
+		key := strings.TrimSpace(line[:i])
+		if key == "" {
+			return nil, fmt.Errorf("line %d: empty key", n)
+		}
+		f.Set(section, key, unquote(strings.TrimSpace(line[i+1:])))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Sections returns the sections in the order they were first defined
+func (f *File) Sections() []*Section {
+	return f.sections
+}
+
+// Get returns the value of a key and whether it is set
+func (f *File) Get(section, key string) (string, bool) {
+	s := f.section(section, false)
+	if s == nil {
+		return "", false
+	}
+	value, ok := s.vals[key]
+	return value, ok
+}
+
+// Set assigns a key, appending the section and key when they are new
+func (f *File) Set(section, key, value string) {
+	s := f.section(section, true)
+	if _, ok := s.vals[key]; !ok {
+		s.keys = append(s.keys, key)
+	}
+	s.vals[key] = value
+}
+
+// Merge sets every key of other, overriding the keys already present
+func (f *File) Merge(other *File) {
+	for _, s := range other.sections {
+		f.section(s.Name, true)
+		for _, key := range s.keys {
+			f.Set(s.Name, key, s.vals[key])
+		}
+	}
+}
+
+// String serializes the file, sections and keys in definition order
+func (f *File) String() string {
+	var b strings.Builder
+	for _, s := range f.sections {
+		if s.Name != "" {
+			if b.Len() > 0 {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "[%s]\n", s.Name)
+		}
+		for _, key := range s.keys {
+			fmt.Fprintf(&b, "%s = %s\n", key, quote(s.vals[key]))
+		}
+	}
+	return b.String()
+}
+
+// Keys returns the keys of the section in definition order
+func (s *Section) Keys() []string {
+	return s.keys
+}
+
+// Value returns the value of a key of the section
+func (s *Section) Value(key string) string {
+	return s.vals[key]
+}
+
+func (f *File) section(name string, create bool) *Section {
+	for _, s := range f.sections {
+		if s.Name == name {
+			return s
+		}
+	}
+	if !create {
+		return nil
+	}
+
+	s := &Section{Name: name, vals: map[string]string{}}
+	if name == "" {
+		// Keys outside of a section must be written before the first header
+		f.sections = append([]*Section{s}, f.sections...)
+	} else {
+		f.sections = append(f.sections, s)
+	}
+	return s
+}
+
+// quote wraps values grafana would otherwise cut at a comment character
+func quote(value string) string {
+	if strings.ContainsAny(value, "#;") {
+		return `"""` + value + `"""`
+	}
+	return value
+}
+
+func unquote(value string) string {
+	if len(value) >= 6 && strings.HasPrefix(value, `"""`) && strings.HasSuffix(value, `"""`) {
+		return value[3 : len(value)-3]
+	}
+	return value
+}
@@ -0,0 +1,150 @@
+package ini
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected map[string]map[string]string
+		failed   bool
+	}{
+		{
+			name: "sections and comments",
+			data: "# comment\n; comment\n\n[server]\nhttp_port = 3000\n[auth]\ndisable_login_form=true\n",
+			expected: map[string]map[string]string{
+				"server": {"http_port": "3000"},
+				"auth":   {"disable_login_form": "true"},
+			},
+		},
+		{
+			name:     "keys before the first section",
+			data:     "app_mode = production\n[paths]\ndata = /var/lib/grafana\n",
+			expected: map[string]map[string]string{"": {"app_mode": "production"}, "paths": {"data": "/var/lib/grafana"}},
+		},
+		{
+			name:     "triple quoted value",
+			data:     "[database]\npassword = \"\"\"p#ss;word\"\"\"\n",
+			expected: map[string]map[string]string{"database": {"password": "p#ss;word"}},
+		},
+		{
+			name:     "empty value",
+			data:     "[smtp]\nhost =\n",
+			expected: map[string]map[string]string{"smtp": {"host": ""}},
+		},
+		{
+			name:     "value with equal sign",
+			data:     "[auth.generic_oauth]\nauth_url = https://example.com/auth?a=b\n",
+			expected: map[string]map[string]string{"auth.generic_oauth": {"auth_url": "https://example.com/auth?a=b"}},
+		},
+		{name: "unterminated section", data: "[server\n", failed: true},
+		{name: "missing equal sign", data: "[server]\nhttp_port\n", failed: true},
+		{name: "empty key", data: "[server]\n= 3000\n", failed: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f, err := Parse(test.data)
+			if test.failed {
+				if err == nil {
+					t.Fatalf("expected an error parsing %q", test.data)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			for section, keys := range test.expected {
+				for key, expected := range keys {
+					if value, ok := f.Get(section, key); !ok || value != expected {
+						t.Errorf("expected [%s] %s = %q, got %q", section, key, expected, value)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		override string
+		expected string
+	}{
+		{
+			name:     "override and append keys",
+			base:     "[server]\nhttp_port = 3000\nprotocol = http\n",
+			override: "[server]\nhttp_port = 8080\nroot_url = /grafana\n",
+			expected: "[server]\nhttp_port = 8080\nprotocol = http\nroot_url = /grafana\n",
+		},
+		{
+			name:     "new section",
+			base:     "[server]\nhttp_port = 3000\n",
+			override: "[auth]\ndisable_login_form = true\n",
+			expected: "[server]\nhttp_port = 3000\n\n[auth]\ndisable_login_form = true\n",
+		},
+		{
+			name:     "keys outside of sections stay first",
+			base:     "[server]\nhttp_port = 3000\n",
+			override: "app_mode = development\n",
+			expected: "app_mode = development\n\n[server]\nhttp_port = 3000\n",
+		},
+		{
+			name:     "empty override",
+			base:     "[server]\nhttp_port = 3000\n",
+			expected: "[server]\nhttp_port = 3000\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			base, err := Parse(test.base)
+			if err != nil {
+				t.Fatal(err)
+			}
+			override, err := Parse(test.override)
+			if err != nil {
+				t.Fatal(err)
+			}
+			base.Merge(override)
+			if s := base.String(); s != test.expected {
+				t.Errorf("expected\n%s\ngot\n%s", test.expected, s)
+			}
+		})
+	}
+}
+
+func TestStringRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{name: "plain", value: "admin", expected: "[security]\nkey = admin\n"},
+		{name: "comment character", value: "p#ss", expected: "[security]\nkey = \"\"\"p#ss\"\"\"\n"},
+		{name: "semicolon", value: "a;b", expected: "[security]\nkey = \"\"\"a;b\"\"\"\n"},
+		{name: "empty", value: "", expected: "[security]\nkey = \n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := New()
+			f.Set("security", "key", test.value)
+			s := f.String()
+			if s != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, s)
+			}
+
+			parsed, err := Parse(s)
+			if err != nil {
+				t.Fatalf("parsing %q: %s", s, err)
+			}
+			if value, _ := parsed.Get("security", "key"); value != test.value {
+				t.Errorf("round trip: expected %q, got %q", test.value, value)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package util
+
+import (
+	"sort"
+
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog"
+)
+
+const builtinDashboardsVolume string = "builtin-dashboards"
+
+// dashboardBundles registers the built-in dashboard bundles by name with the files
+// they ship in config/templates/dashboards.
+var dashboardBundles = map[string][]string{
+	"kafka":     {"strimzi-kafka.json", "strimzi-kafka-exporter.json"},
+	"zookeeper": {"strimzi-zookeeper.json"},
+	"rabbitmq":  {"rabbitmq.json"},
+	"burrow":    {"dichque-burrow.json"},
+}
+
+// defaultDashboardBundles are rendered when spec.dashboardBundles is unset, matching
+// the dashboards installed before bundles were selectable.
+var defaultDashboardBundles = []string{"kafka", "zookeeper"}
+
+// DashboardBundleNames returns the names of all registered bundles in a stable order
+func DashboardBundleNames() []string {
+	names := []string{}
+	for name := range dashboardBundles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DashboardBundleName returns the name of the configmap holding a bundle for the instance
+func DashboardBundleName(grafana *aimsv1.Grafana, bundle string) string {
+	return ConfigMapName(grafana, bundle+"-dashboards")
+}
+
+// SelectedDashboardBundles returns the known bundles selected by the instance, sorted and
+// without duplicates. Unknown names are logged and skipped.
+func SelectedDashboardBundles(grafana *aimsv1.Grafana) []string {
+	requested := grafana.Spec.DashboardBundles
+	if requested == nil {
+		requested = defaultDashboardBundles
+	}
+
+	seen := map[string]bool{}
+	bundles := []string{}
+	for _, bundle := range requested {
+		if _, ok := dashboardBundles[bundle]; !ok {
+			klog.Errorf("unknown dashboard bundle %s on grafana %s/%s", bundle, grafana.Namespace, grafana.Name)
+			continue
+		}
+		if !seen[bundle] {
+			seen[bundle] = true
+			bundles = append(bundles, bundle)
+		}
+	}
+	sort.Strings(bundles)
+	return bundles
+}
+
+// dashboardBundleConfigMaps returns a configmap per selected bundle
+func dashboardBundleConfigMaps(grafana *aimsv1.Grafana) []v1.ConfigMap {
+	cms := []v1.ConfigMap{}
+	for _, bundle := range SelectedDashboardBundles(grafana) {
+		cms = append(cms, ownedConfigMap(grafana, DashboardBundleName(grafana, bundle), buildCMData(dashboardBundles[bundle])))
+	}
+	return cms
+}
+
+// builtinDashboardsVolumeSource projects the configmaps of the selected bundles into one directory
+func builtinDashboardsVolumeSource(grafana *aimsv1.Grafana) v1.VolumeSource {
+	sources := []v1.VolumeProjection{}
+	for _, bundle := range SelectedDashboardBundles(grafana) {
+		sources = append(sources, v1.VolumeProjection{
+			ConfigMap: &v1.ConfigMapProjection{
+				LocalObjectReference: v1.LocalObjectReference{Name: DashboardBundleName(grafana, bundle)},
+			},
+		})
+	}
+	return v1.VolumeSource{Projected: &v1.ProjectedVolumeSource{Sources: sources}}
+}
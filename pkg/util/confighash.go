@@ -0,0 +1,81 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+// ConfigHash returns a digest of the configmaps and secrets grafana reads its configuration
+// from at startup. Dashboard bundles are left out since the file provider reloads dashboards
+// without a restart.
+func ConfigHash(grafana *aimsv1.Grafana, cms []v1.ConfigMap, secrets []*v1.Secret) string {
+	bundles := map[string]bool{}
+	for _, bundle := range DashboardBundleNames() {
+		bundles[DashboardBundleName(grafana, bundle)] = true
+	}
+
+	data := map[string]map[string]string{}
+	for _, cm := range cms {
+		if bundles[cm.Name] {
+			continue
+		}
+		data["configmap/"+cm.Name] = cm.Data
+	}
+	for _, secret := range secrets {
+		if secret == nil {
+			continue
+		}
+		values := map[string]string{}
+		for key, value := range secret.Data {
+			values[key] = string(value)
+		}
+		data["secret/"+secret.Name] = values
+	}
+	return hash(data)
+}
+
+// CredentialsHash returns a digest of the credentials the pods read from secrets, by secret
+// name and key, so that only changes of those values roll the pods
+func CredentialsHash(credentials map[string]map[string]string) string {
+	data := map[string]map[string]string{}
+	for name, values := range credentials {
+		data["secret/"+name] = values
+	}
+	return hash(data)
+}
+
+// hash digests data in a stable order, with the length of each value so that values cannot
+// shift into one another
+func hash(data map[string]map[string]string) string {
+	h := sha256.New()
+	for _, name := range sortedKeys(data) {
+		fmt.Fprintf(h, "%s\n", name)
+		for _, key := range sortedStringKeys(data[name]) {
+			fmt.Fprintf(h, "%s=%d:%s\n", key, len(data[name][key]), data[name][key])
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func sortedKeys(m map[string]map[string]string) []string {
+	keys := []string{}
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedStringKeys(m map[string]string) []string {
+	keys := []string{}
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,59 @@
+package util
+
+import (
+	"testing"
+)
+
+func TestCredentialsHash(t *testing.T) {
+	base := map[string]map[string]string{
+		"admin": {"GF_SECURITY_ADMIN_USER": "admin", "GF_SECURITY_ADMIN_PASSWORD": "secret"},
+		"db":    {"password": "db"},
+	}
+
+	tests := []struct {
+		name        string
+		credentials map[string]map[string]string
+		changed     bool
+	}{
+		{
+			name: "same values",
+			credentials: map[string]map[string]string{
+				"db":    {"password": "db"},
+				"admin": {"GF_SECURITY_ADMIN_PASSWORD": "secret", "GF_SECURITY_ADMIN_USER": "admin"},
+			},
+		},
+		{
+			name: "password changed",
+			credentials: map[string]map[string]string{
+				"admin": {"GF_SECURITY_ADMIN_USER": "admin", "GF_SECURITY_ADMIN_PASSWORD": "other"},
+				"db":    {"password": "db"},
+			},
+			changed: true,
+		},
+		{
+			name: "value moved between keys",
+			credentials: map[string]map[string]string{
+				"admin": {"GF_SECURITY_ADMIN_USER": "adminsecret", "GF_SECURITY_ADMIN_PASSWORD": ""},
+				"db":    {"password": "db"},
+			},
+			changed: true,
+		},
+		{
+			name: "secret renamed",
+			credentials: map[string]map[string]string{
+				"admin2": {"GF_SECURITY_ADMIN_USER": "admin", "GF_SECURITY_ADMIN_PASSWORD": "secret"},
+				"db":     {"password": "db"},
+			},
+			changed: true,
+		},
+	}
+
+	expected := CredentialsHash(base)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if changed := CredentialsHash(test.credentials) != expected; changed != test.changed {
+				t.Errorf("expected changed %v, got %v", test.changed, changed)
+			}
+		})
+	}
+}
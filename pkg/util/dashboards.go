@@ -0,0 +1,262 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog"
+)
+
+const (
+	// dashboardFolderAnnotation names the folder of the dashboards in a custom dashboards configmap
+	dashboardFolderAnnotation string = "aims.cisco.com/dashboard-folder"
+
+	builtinDashboardsPath string = "/grafana-dashboard-definitions/0"
+	customDashboardsPath  string = "/grafana-dashboard-definitions/custom"
+)
+
+// DashboardFolder identifies the grafana folder a dashboard is provisioned into. Dashboards
+// without a UID go to a folder looked up by title, the empty title being General.
+type DashboardFolder struct {
+	Title string `json:"title,omitempty"`
+	UID   string `json:"uid,omitempty"`
+}
+
+// DashboardFolders maps dashboard file names to their folder
+type DashboardFolders map[string]DashboardFolder
+
+type dashboardProvider struct {
+	Folder    string                 `json:"folder"`
+	FolderUID string                 `json:"folderUid,omitempty"`
+	Name      string                 `json:"name"`
+	Options   dashboardProviderPaths `json:"options"`
+	OrgID     int                    `json:"orgId"`
+	Type      string                 `json:"type"`
+}
+
+type dashboardProviderPaths struct {
+	Path string `json:"path"`
+}
+
+type dashboardProviders struct {
+	APIVersion int                 `json:"apiVersion"`
+	Providers  []dashboardProvider `json:"providers"`
+}
+
+// CustomDashboardsName returns the name of the configmap holding the GrafanaDashboard
+// resources of the General folder. Other folders get a configmap of their own, see
+// CustomDashboardsFolderName.
+func CustomDashboardsName(grafana *aimsv1.Grafana) string {
+	return grafana.Name + "-grafana-custom-dashboards"
+}
+
+// CustomDashboardsFolderName returns the name of the configmap holding the GrafanaDashboard
+// resources of a folder
+func CustomDashboardsFolderName(grafana *aimsv1.Grafana, folder DashboardFolder) string {
+	if folder == (DashboardFolder{}) {
+		return CustomDashboardsName(grafana)
+	}
+	h := sha256.Sum256([]byte(folder.Title + "\x00" + folder.UID))
+	return CustomDashboardsName(grafana) + "-" + hex.EncodeToString(h[:])[:10]
+}
+
+// DashboardKey returns the configmap key of the named GrafanaDashboard
+func DashboardKey(name string) string {
+	return name + ".json"
+}
+
+// DashboardMatches reports whether a GrafanaDashboard selects the grafana instance
+func DashboardMatches(dashboard *aimsv1.GrafanaDashboard, grafana *aimsv1.Grafana) bool {
+	return grafanaSelected(&dashboard.ObjectMeta, dashboard.Spec.GrafanaSelector, grafana)
+}
+
+// grafanaSelected reports whether a namespaced resource with a grafanaSelector selects the instance
+func grafanaSelected(meta *metav1.ObjectMeta, grafanaSelector *metav1.LabelSelector, grafana *aimsv1.Grafana) bool {
+	if meta.Namespace != grafana.Namespace {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(grafanaSelector)
+	if err != nil {
+		klog.Errorf("invalid grafanaSelector on %s/%s: %s", meta.Namespace, meta.Name, err)
+		return false
+	}
+	return selector.Matches(labels.Set(grafana.Labels))
+}
+
+// CustomDashboardsConfigMap returns an empty custom dashboards configmap of a folder, owned
+// by the instance
+func CustomDashboardsConfigMap(grafana *aimsv1.Grafana, folder DashboardFolder) *v1.ConfigMap {
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      CustomDashboardsFolderName(grafana, folder),
+			Namespace: grafana.Namespace,
+			Labels:    Labels(grafana),
+		},
+		Data: map[string]string{},
+	}
+	setDashboardFolder(cm, folder)
+
+	owner := metav1.NewControllerRef(
+		grafana, aimsv1.SchemeGroupVersion.
+			WithKind("Grafana"),
+	)
+	cm.ObjectMeta.OwnerReferences = append(cm.ObjectMeta.OwnerReferences, *owner)
+
+	return cm
+}
+
+// CustomDashboardsConfigMaps filters the configmaps of an instance down to its custom
+// dashboards configmaps
+func CustomDashboardsConfigMaps(grafana *aimsv1.Grafana, cms []*v1.ConfigMap) []*v1.ConfigMap {
+	filtered := []*v1.ConfigMap{}
+	for _, cm := range cms {
+		if _, ok := cm.Annotations[dashboardFolderAnnotation]; ok || cm.Name == CustomDashboardsName(grafana) {
+			filtered = append(filtered, cm)
+		}
+	}
+	return filtered
+}
+
+// CustomDashboardsFolder returns the folder the dashboards of a custom dashboards configmap
+// are provisioned into
+func CustomDashboardsFolder(cm *v1.ConfigMap) DashboardFolder {
+	folder := DashboardFolder{}
+	if raw := cm.Annotations[dashboardFolderAnnotation]; raw != "" {
+		if err := json.Unmarshal([]byte(raw), &folder); err != nil {
+			klog.Errorf("invalid %s annotation on configmap %s/%s: %s", dashboardFolderAnnotation, cm.Namespace, cm.Name, err)
+		}
+	}
+	return folder
+}
+
+// CustomDashboardFolders returns the folder of every dashboard in the custom dashboards
+// configmaps of an instance
+func CustomDashboardFolders(cms []*v1.ConfigMap) DashboardFolders {
+	folders := DashboardFolders{}
+	for _, cm := range cms {
+		folder := CustomDashboardsFolder(cm)
+		for key := range cm.Data {
+			folders[key] = folder
+		}
+	}
+	return folders
+}
+
+// SetCustomDashboard stores a dashboard in the custom dashboards configmap of its folder and
+// reports whether it changed
+func SetCustomDashboard(cm *v1.ConfigMap, key, model string, folder DashboardFolder) bool {
+	changed := false
+	if _, ok := cm.Annotations[dashboardFolderAnnotation]; !ok || CustomDashboardsFolder(cm) != folder {
+		setDashboardFolder(cm, folder)
+		changed = true
+	}
+	if current, ok := cm.Data[key]; ok && current == model {
+		return changed
+	}
+
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[key] = model
+	return true
+}
+
+// RemoveCustomDashboard drops a dashboard from a custom dashboards configmap and reports whether it was present
+func RemoveCustomDashboard(cm *v1.ConfigMap, key string) bool {
+	if _, ok := cm.Data[key]; !ok {
+		return false
+	}
+	delete(cm.Data, key)
+	return true
+}
+
+func setDashboardFolder(cm *v1.ConfigMap, folder DashboardFolder) {
+	data, err := json.Marshal(folder)
+	if err != nil {
+		klog.Errorf("unable to encode dashboard folder: %s", err)
+		return
+	}
+	if cm.Annotations == nil {
+		cm.Annotations = map[string]string{}
+	}
+	cm.Annotations[dashboardFolderAnnotation] = string(data)
+}
+
+// distinctFolders returns the distinct folders in a stable order. The index of a folder
+// is used as the directory its dashboards are mounted into.
+func distinctFolders(folders DashboardFolders) []DashboardFolder {
+	seen := map[DashboardFolder]bool{}
+	distinct := []DashboardFolder{}
+	for _, folder := range folders {
+		if !seen[folder] {
+			seen[folder] = true
+			distinct = append(distinct, folder)
+		}
+	}
+	sort.Slice(distinct, func(i, j int) bool {
+		if distinct[i].Title != distinct[j].Title {
+			return distinct[i].Title < distinct[j].Title
+		}
+		return distinct[i].UID < distinct[j].UID
+	})
+	return distinct
+}
+
+// dashboardProvidersConfig renders the dashboards.yaml provider file
+func dashboardProvidersConfig(folders DashboardFolders) string {
+	providers := dashboardProviders{
+		APIVersion: 1,
+		Providers: []dashboardProvider{
+			{Folder: "", Name: "0", Options: dashboardProviderPaths{Path: builtinDashboardsPath}, OrgID: 1, Type: "file"},
+		},
+	}
+
+	for i, folder := range distinctFolders(folders) {
+		providers.Providers = append(providers.Providers, dashboardProvider{
+			Folder:    folder.Title,
+			FolderUID: folder.UID,
+			Name:      fmt.Sprintf("custom-%d", i),
+			Options:   dashboardProviderPaths{Path: fmt.Sprintf("%s/%d", customDashboardsPath, i)},
+			OrgID:     1,
+			Type:      "file",
+		})
+	}
+
+	data, err := json.MarshalIndent(providers, "", "    ")
+	if err != nil {
+		klog.Errorf("unable to encode dashboard providers: %s", err)
+	}
+	return string(data)
+}
+
+// customDashboardVolumes mounts the custom dashboards configmap of every folder into the
+// directory of its provider. Whole configmaps are mounted so that adding or removing a
+// dashboard is synced by the kubelet and leaves the pod template alone.
+func customDashboardVolumes(grafana *aimsv1.Grafana, folders DashboardFolders) ([]v1.Volume, []v1.VolumeMount) {
+	optional := true
+	volumes := []v1.Volume{}
+	mounts := []v1.VolumeMount{}
+	for i, folder := range distinctFolders(folders) {
+		name := fmt.Sprintf("custom-dashboards-%d", i)
+		volumes = append(volumes, v1.Volume{
+			Name: name,
+			VolumeSource: v1.VolumeSource{
+				ConfigMap: &v1.ConfigMapVolumeSource{
+					LocalObjectReference: v1.LocalObjectReference{
+						Name: CustomDashboardsFolderName(grafana, folder),
+					},
+					Optional: &optional,
+				},
+			},
+		})
+		mounts = append(mounts, v1.VolumeMount{Name: name, MountPath: fmt.Sprintf("%s/%d", customDashboardsPath, i)})
+	}
+	return volumes, mounts
+}
@@ -0,0 +1,80 @@
+package util
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+)
+
+func TestDeploymentDashboardVolumes(t *testing.T) {
+	kafka := DashboardFolder{Title: "Kafka"}
+	tests := []struct {
+		name    string
+		before  DashboardFolders
+		after   DashboardFolders
+		rollout bool
+	}{
+		{name: "no dashboards", before: DashboardFolders{}, after: DashboardFolders{}},
+		{name: "dashboard added", before: DashboardFolders{"a.json": {}}, after: DashboardFolders{"a.json": {}, "b.json": {}}},
+		{name: "dashboard removed", before: DashboardFolders{"a.json": kafka, "b.json": kafka}, after: DashboardFolders{"a.json": kafka}},
+		{name: "folder added", before: DashboardFolders{"a.json": {}}, after: DashboardFolders{"a.json": {}, "b.json": kafka}, rollout: true},
+		{name: "folder removed", before: DashboardFolders{"a.json": {}, "b.json": kafka}, after: DashboardFolders{"a.json": {}}, rollout: true},
+	}
+
+	grafana := &aimsv1.Grafana{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns"}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			before := Deployment(grafana, test.before).Spec.Template
+			after := Deployment(grafana, test.after).Spec.Template
+			if changed := !equality.Semantic.DeepEqual(before, after); changed != test.rollout {
+				t.Errorf("expected the pod template to change: %v, changed: %v", test.rollout, changed)
+			}
+			for _, volume := range after.Spec.Volumes {
+				if volume.ConfigMap != nil && len(volume.ConfigMap.Items) > 0 {
+					t.Errorf("volume %s lists configmap keys", volume.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestCustomDashboardsFolderName(t *testing.T) {
+	grafana := &aimsv1.Grafana{ObjectMeta: metav1.ObjectMeta{Name: "a"}}
+	folders := []DashboardFolder{{}, {Title: "Kafka"}, {Title: "Kafka", UID: "kafka"}, {UID: "kafka"}}
+
+	names := map[string]bool{}
+	for _, folder := range folders {
+		name := CustomDashboardsFolderName(grafana, folder)
+		if names[name] {
+			t.Errorf("folder %+v shares configmap %s", folder, name)
+		}
+		names[name] = true
+	}
+	if name := CustomDashboardsFolderName(grafana, DashboardFolder{}); name != CustomDashboardsName(grafana) {
+		t.Errorf("expected General dashboards in %s, got %s", CustomDashboardsName(grafana), name)
+	}
+}
+
+func TestSetCustomDashboard(t *testing.T) {
+	grafana := &aimsv1.Grafana{ObjectMeta: metav1.ObjectMeta{Name: "a"}}
+	kafka := DashboardFolder{Title: "Kafka"}
+
+	cm := CustomDashboardsConfigMap(grafana, kafka)
+	if !SetCustomDashboard(cm, "a.json", "{}", kafka) {
+		t.Error("expected a new dashboard to change the configmap")
+	}
+	if SetCustomDashboard(cm, "a.json", "{}", kafka) {
+		t.Error("expected the same dashboard to leave the configmap alone")
+	}
+	if folders := CustomDashboardFolders([]*v1.ConfigMap{cm}); folders["a.json"] != kafka {
+		t.Errorf("expected a.json in %+v, got %+v", kafka, folders)
+	}
+
+	if !RemoveCustomDashboard(cm, "a.json") || RemoveCustomDashboard(cm, "a.json") {
+		t.Error("expected a dashboard to be removed once")
+	}
+}
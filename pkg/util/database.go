@@ -0,0 +1,78 @@
+package util
+
+import (
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+	"github.com/dichque/grafana-operator/pkg/ini"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// setDatabase points grafana.ini at the external database. Sessions and the remote cache
+// move to the database as well so replicas share logins.
+func setDatabase(file *ini.File, db *aimsv1.GrafanaDatabase) {
+	file.Set("database", "type", string(db.Type))
+	file.Set("database", "host", db.Host)
+	file.Set("database", "name", db.Name)
+	file.Set("database", "user", db.User)
+	if db.SSLMode != "" && db.Type == aimsv1.DatabaseTypePostgres {
+		file.Set("database", "ssl_mode", db.SSLMode)
+	}
+	file.Set("remote_cache", "type", "database")
+}
+
+// SecretKeySecretName returns the secret holding the secret_key shared by the replicas
+func SecretKeySecretName(grafana *aimsv1.Grafana) string {
+	if GeneratesAdminSecret(grafana) {
+		return AdminSecretName(grafana)
+	}
+	return grafana.Name + "-grafana-secret-key"
+}
+
+// NeedsSecretKeySecret reports whether the shared secret_key needs a secret of its own, which
+// is the case with an external database and admin credentials the operator does not generate.
+func NeedsSecretKeySecret(grafana *aimsv1.Grafana) bool {
+	return grafana.Spec.Database != nil && !GeneratesAdminSecret(grafana)
+}
+
+// SecretKeySecret returns a secret with a random secret_key owned by the instance
+func SecretKeySecret(grafana *aimsv1.Grafana) (*v1.Secret, error) {
+	secretKey, err := RandomString(32)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      SecretKeySecretName(grafana),
+			Namespace: grafana.Namespace,
+			Labels:    Labels(grafana),
+		},
+		Type: v1.SecretTypeOpaque,
+		Data: map[string][]byte{secretKeyKey: []byte(secretKey)},
+	}
+
+	owner := metav1.NewControllerRef(
+		grafana, aimsv1.SchemeGroupVersion.
+			WithKind("Grafana"),
+	)
+	secret.ObjectMeta.OwnerReferences = append(secret.ObjectMeta.OwnerReferences, *owner)
+
+	return secret, nil
+}
+
+// DatabaseEnv returns the database password and shared secret_key env vars
+func DatabaseEnv(grafana *aimsv1.Grafana) []v1.EnvVar {
+	db := grafana.Spec.Database
+	if db == nil {
+		return nil
+	}
+
+	env := []v1.EnvVar{}
+	if NeedsSecretKeySecret(grafana) {
+		env = append(env, secretEnv("GF_SECURITY_SECRET_KEY", SecretKeySecretName(grafana), secretKeyKey))
+	}
+	if db.PasswordSecretRef != nil {
+		env = append(env, secretEnv("GF_DATABASE_PASSWORD", db.PasswordSecretRef.Name, db.PasswordSecretRef.Key))
+	}
+	return env
+}
@@ -0,0 +1,145 @@
+package util
+
+import (
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+	"github.com/dichque/grafana-operator/pkg/config"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog"
+)
+
+// SecureDatasourcesKey is the file name of the secret provisioned datasources
+const SecureDatasourcesKey string = "secure-datasources.yaml"
+
+// legacyPrometheusJSONData is the jsonData of the datasource created from prometheus_url
+const legacyPrometheusJSONData = `{"severity_critical":"4","severity_high":"3","severity_info":"1","severity_warning":"2","timeInterval":"15s"}`
+
+// SecureJSONData holds the resolved secureJsonDataFrom values keyed by datasource name
+type SecureJSONData map[string]map[string]string
+
+// Datasources returns the provisioned datasources of an instance, being its inline datasources
+// followed by the selected external ones. The prometheus_url shorthand adds the historical
+// "prometheus" datasource unless the list already defines one by that name.
+func Datasources(grafana *aimsv1.Grafana, external []aimsv1.GrafanaDatasource, secure SecureJSONData) []config.Datasource {
+	datasources := []config.Datasource{}
+	hasDefault, hasPrometheus := false, false
+
+	all := append(append([]aimsv1.GrafanaDatasource{}, grafana.Spec.Datasources...), external...)
+	for _, ds := range all {
+		hasDefault = hasDefault || ds.IsDefault
+		hasPrometheus = hasPrometheus || ds.Name == "prometheus"
+
+		access := ds.Access
+		if access == "" {
+			access = "proxy"
+		}
+		jsonData := ""
+		if ds.JSONData != nil && len(ds.JSONData.Raw) > 0 {
+			jsonData = string(ds.JSONData.Raw)
+		}
+
+		secureJSONData := map[string]string{}
+		for key, value := range ds.SecureJSONData {
+			secureJSONData[key] = value
+		}
+		for key, value := range secure[ds.Name] {
+			secureJSONData[key] = value
+		}
+
+		datasources = append(datasources, config.Datasource{
+			Name:           ds.Name,
+			Type:           ds.Type,
+			URL:            ds.URL,
+			Access:         access,
+			IsDefault:      ds.IsDefault,
+			JSONData:       jsonData,
+			SecureJSONData: secureJSONData,
+			Secure:         len(ds.SecureJSONData) > 0 || len(ds.SecureJSONDataFrom) > 0,
+		})
+	}
+
+	if grafana.Spec.PrometheusURL != "" && !hasPrometheus {
+		legacy := config.Datasource{
+			Name:      "prometheus",
+			Type:      "prometheus",
+			URL:       grafana.Spec.PrometheusURL,
+			Access:    "proxy",
+			IsDefault: !hasDefault,
+			JSONData:  legacyPrometheusJSONData,
+		}
+		datasources = append([]config.Datasource{legacy}, datasources...)
+	}
+
+	return datasources
+}
+
+// DatasourceSecretName returns the name of the secret provisioning secure datasources
+func DatasourceSecretName(grafana *aimsv1.Grafana) string {
+	return grafana.Name + "-grafana-datasources"
+}
+
+// DatasourceSecret renders the datasources carrying secure data into a provisioning secret
+// that is projected next to the datasources configmap.
+func DatasourceSecret(grafana *aimsv1.Grafana, external []aimsv1.GrafanaDatasource, secure SecureJSONData) *v1.Secret {
+	gcfg := &config.GrafanaConfig{
+		Datasources: filterDatasources(Datasources(grafana, external, secure), true),
+	}
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DatasourceSecretName(grafana),
+			Namespace: grafana.Namespace,
+			Labels:    Labels(grafana),
+		},
+		Type: v1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			SecureDatasourcesKey: []byte(renderTemplate(configTmplPath["grafana-datasources"], gcfg)),
+		},
+	}
+
+	owner := metav1.NewControllerRef(
+		grafana, aimsv1.SchemeGroupVersion.
+			WithKind("Grafana"),
+	)
+	secret.ObjectMeta.OwnerReferences = append(secret.ObjectMeta.OwnerReferences, *owner)
+
+	return secret
+}
+
+// DataSourceSelected reports whether the datasourceSelector of an instance selects a GrafanaDataSource.
+// Datasources of another namespace are only selected when they allow the instance namespace.
+func DataSourceSelected(grafana *aimsv1.Grafana, ds *aimsv1.GrafanaDataSource) bool {
+	if grafana.Spec.DatasourceSelector == nil || !dataSourceAllows(ds, grafana.Namespace) {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(grafana.Spec.DatasourceSelector)
+	if err != nil {
+		klog.Errorf("invalid datasourceSelector on grafana %s/%s: %s", grafana.Namespace, grafana.Name, err)
+		return false
+	}
+	return selector.Matches(labels.Set(ds.Labels))
+}
+
+// dataSourceAllows reports whether Grafana resources of namespace may select ds
+func dataSourceAllows(ds *aimsv1.GrafanaDataSource, namespace string) bool {
+	if ds.Namespace == namespace {
+		return true
+	}
+	for _, allowed := range ds.Spec.AllowedNamespaces {
+		if allowed == "*" || allowed == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+func filterDatasources(datasources []config.Datasource, secure bool) []config.Datasource {
+	filtered := []config.Datasource{}
+	for _, ds := range datasources {
+		if ds.Secure == secure {
+			filtered = append(filtered, ds)
+		}
+	}
+	return filtered
+}
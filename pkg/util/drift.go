@@ -0,0 +1,135 @@
+package util
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+// defaultVolumeMode is the mode the API server defaults configmap, secret and projected volumes to
+const defaultVolumeMode int32 = 0644
+
+// MergeDeployment applies the fields the operator owns from desired onto a copy of the live
+// deployment found and returns it with the names of the fields that drifted. Fields owned by
+// other actors are kept, and so are the replicas unless manageReplicas is set.
+func MergeDeployment(found, desired *appsv1.Deployment, manageReplicas bool) (*appsv1.Deployment, []string) {
+	deploy := found.DeepCopy()
+	drifted := []string{}
+
+	if manageReplicas && desired.Spec.Replicas != nil &&
+		(found.Spec.Replicas == nil || *found.Spec.Replicas != *desired.Spec.Replicas) {
+		replicas := *desired.Spec.Replicas
+		deploy.Spec.Replicas = &replicas
+		drifted = append(drifted, "replicas")
+	}
+
+	if found.Spec.Strategy.Type != desired.Spec.Strategy.Type {
+		deploy.Spec.Strategy = desired.Spec.Strategy
+		drifted = append(drifted, "strategy")
+	}
+
+	template := &deploy.Spec.Template
+	if mergeStringMap(&deploy.Labels, desired.Labels) {
+		drifted = append(drifted, "metadata.labels")
+	}
+	if mergeStringMap(&template.Labels, desired.Spec.Template.Labels) {
+		drifted = append(drifted, "labels")
+	}
+	if mergeStringMap(&template.Annotations, desired.Spec.Template.Annotations) {
+		drifted = append(drifted, "annotations")
+	}
+
+	podSpec, want := &template.Spec, &desired.Spec.Template.Spec
+	if !equality.Semantic.DeepEqual(podSpec.ImagePullSecrets, want.ImagePullSecrets) {
+		podSpec.ImagePullSecrets = want.ImagePullSecrets
+		drifted = append(drifted, "imagePullSecrets")
+	}
+	if securityContext := defaultSecurityContext(want.SecurityContext); !equality.Semantic.DeepEqual(podSpec.SecurityContext, securityContext) {
+		podSpec.SecurityContext = securityContext
+		drifted = append(drifted, "securityContext")
+	}
+	if volumes := defaultVolumes(want.Volumes); !equality.Semantic.DeepEqual(podSpec.Volumes, volumes) {
+		podSpec.Volumes = volumes
+		drifted = append(drifted, "volumes")
+	}
+
+	for _, wantContainer := range want.Containers {
+		container := findContainer(podSpec.Containers, wantContainer.Name)
+		if container == nil {
+			podSpec.Containers = want.Containers
+			drifted = append(drifted, "containers")
+			break
+		}
+		if container.Image != wantContainer.Image {
+			container.Image = wantContainer.Image
+			drifted = append(drifted, "image")
+		}
+		if !equality.Semantic.DeepEqual(container.Env, wantContainer.Env) {
+			container.Env = wantContainer.Env
+			drifted = append(drifted, "env")
+		}
+		if !equality.Semantic.DeepEqual(container.VolumeMounts, wantContainer.VolumeMounts) {
+			container.VolumeMounts = wantContainer.VolumeMounts
+			drifted = append(drifted, "volumeMounts")
+		}
+		if !equality.Semantic.DeepEqual(container.Resources, wantContainer.Resources) {
+			container.Resources = wantContainer.Resources
+			drifted = append(drifted, "resources")
+		}
+	}
+
+	return deploy, drifted
+}
+
+// mergeStringMap sets every key of want on m and reports whether any value changed
+func mergeStringMap(m *map[string]string, want map[string]string) bool {
+	changed := false
+	for key, value := range want {
+		if current, ok := (*m)[key]; ok && current == value {
+			continue
+		}
+		if *m == nil {
+			*m = map[string]string{}
+		}
+		(*m)[key] = value
+		changed = true
+	}
+	return changed
+}
+
+func findContainer(containers []v1.Container, name string) *v1.Container {
+	for i := range containers {
+		if containers[i].Name == name {
+			return &containers[i]
+		}
+	}
+	return nil
+}
+
+// defaultSecurityContext mirrors the empty security context the API server stores for pods without one
+func defaultSecurityContext(sc *v1.PodSecurityContext) *v1.PodSecurityContext {
+	if sc == nil {
+		return &v1.PodSecurityContext{}
+	}
+	return sc
+}
+
+// defaultVolumes applies the API server volume defaults so desired volumes compare equal
+// to the stored ones
+func defaultVolumes(volumes []v1.Volume) []v1.Volume {
+	defaulted := make([]v1.Volume, len(volumes))
+	for i := range volumes {
+		volume := volumes[i].DeepCopy()
+		mode := defaultVolumeMode
+		switch {
+		case volume.ConfigMap != nil && volume.ConfigMap.DefaultMode == nil:
+			volume.ConfigMap.DefaultMode = &mode
+		case volume.Secret != nil && volume.Secret.DefaultMode == nil:
+			volume.Secret.DefaultMode = &mode
+		case volume.Projected != nil && volume.Projected.DefaultMode == nil:
+			volume.Projected.DefaultMode = &mode
+		}
+		defaulted[i] = *volume
+	}
+	return defaulted
+}
@@ -0,0 +1,34 @@
+package util
+
+import (
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+)
+
+// maxFolderUIDLength is the longest uid grafana accepts for a folder
+const maxFolderUIDLength = 40
+
+// FolderUID returns the grafana uid of a GrafanaFolder, defaulting to its name
+func FolderUID(folder *aimsv1.GrafanaFolder) string {
+	uid := folder.Spec.UID
+	if uid == "" {
+		uid = folder.Name
+	}
+	if len(uid) > maxFolderUIDLength {
+		uid = uid[:maxFolderUIDLength]
+	}
+	return uid
+}
+
+// FolderMatches reports whether a GrafanaFolder selects the grafana instance
+func FolderMatches(folder *aimsv1.GrafanaFolder, grafana *aimsv1.Grafana) bool {
+	return grafanaSelected(&folder.ObjectMeta, folder.Spec.GrafanaSelector, grafana)
+}
+
+// FolderOf returns the folder a dashboard is provisioned into. spec.folder names a
+// GrafanaFolder of the namespace, or is taken as a plain title when folder is nil.
+func FolderOf(dashboard *aimsv1.GrafanaDashboard, folder *aimsv1.GrafanaFolder) DashboardFolder {
+	if folder == nil {
+		return DashboardFolder{Title: dashboard.Spec.Folder}
+	}
+	return DashboardFolder{Title: folder.Spec.Title, UID: FolderUID(folder)}
+}
@@ -0,0 +1,66 @@
+package util
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+	"github.com/dichque/grafana-operator/pkg/config"
+	"github.com/dichque/grafana-operator/pkg/ini"
+	"github.com/dichque/grafana-operator/pkg/validation"
+)
+
+// grafanaINIKey is the configmap key grafana reads its configuration from
+const grafanaINIKey string = "grafana.ini"
+
+// GrafanaINI merges spec.config over the operator default grafana.ini
+func GrafanaINI(grafana *aimsv1.Grafana) (string, error) {
+	if err := validation.ValidateConfig(grafana.Spec.Config).ToAggregate(); err != nil {
+		return "", err
+	}
+
+	data, err := ioutil.ReadFile(config.DefaultINIPath)
+	if err != nil {
+		return "", err
+	}
+	file, err := ini.Parse(string(data))
+	if err != nil {
+		return "", fmt.Errorf("invalid %s: %s", config.DefaultINIPath, err)
+	}
+
+	// Admin credentials are passed as env vars from a secret, see AdminCredentialsEnv
+	if grafana.Spec.Database != nil {
+		setDatabase(file, grafana.Spec.Database)
+	}
+
+	if grafana.Spec.Ingress != nil {
+		setServer(file, grafana)
+	}
+
+	file.Merge(configOverrides(grafana.Spec.Config))
+	return file.String(), nil
+}
+
+// configOverrides converts spec.config into an ini file with sorted sections and keys
+func configOverrides(cfg map[string]map[string]string) *ini.File {
+	file := ini.New()
+
+	sections := []string{}
+	for section := range cfg {
+		sections = append(sections, section)
+	}
+	sort.Strings(sections)
+
+	for _, section := range sections {
+		keys := []string{}
+		for key := range cfg[section] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			file.Set(section, key, cfg[section][key])
+		}
+	}
+	return file
+}
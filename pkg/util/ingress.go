@@ -0,0 +1,191 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+	"github.com/dichque/grafana-operator/pkg/ini"
+	v1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// RouteGVR is the OpenShift Route resource, created through the dynamic client
+var RouteGVR = schema.GroupVersionResource{Group: "route.openshift.io", Version: "v1", Resource: "routes"}
+
+// IngressName returns the name of the Ingress or Route exposing the instance
+func IngressName(grafana *aimsv1.Grafana) string {
+	return grafana.Name + "-grafana"
+}
+
+// ingressPath returns spec.ingress.path with a leading and without a trailing slash
+func ingressPath(ingress *aimsv1.GrafanaIngress) string {
+	return "/" + strings.Trim(ingress.Path, "/")
+}
+
+// ExternalURL returns the url grafana is reached at through spec.ingress
+func ExternalURL(grafana *aimsv1.Grafana) string {
+	ingress := grafana.Spec.Ingress
+	if ingress == nil {
+		return ""
+	}
+
+	scheme := "http"
+	if ingress.TLSSecretName != "" {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s%s", scheme, ingress.Host, ingressPath(ingress))
+}
+
+// setServer makes grafana build its links, and serve, below the external url
+func setServer(file *ini.File, grafana *aimsv1.Grafana) {
+	file.Set("server", "domain", grafana.Spec.Ingress.Host)
+	file.Set("server", "root_url", ExternalURL(grafana))
+	if ingressPath(grafana.Spec.Ingress) != "/" {
+		file.Set("server", "serve_from_sub_path", "true")
+	}
+}
+
+// Ingress returns the Ingress routing spec.ingress to the Service of the instance
+func Ingress(grafana *aimsv1.Grafana) *networkingv1beta1.Ingress {
+	spec := grafana.Spec.Ingress
+	ing := &networkingv1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        IngressName(grafana),
+			Namespace:   grafana.Namespace,
+			Labels:      Labels(grafana),
+			Annotations: spec.Annotations,
+		},
+		Spec: networkingv1beta1.IngressSpec{
+			Rules: []networkingv1beta1.IngressRule{
+				{
+					Host: spec.Host,
+					IngressRuleValue: networkingv1beta1.IngressRuleValue{
+						HTTP: &networkingv1beta1.HTTPIngressRuleValue{
+							Paths: []networkingv1beta1.HTTPIngressPath{
+								{
+									Path: ingressPath(spec),
+									Backend: networkingv1beta1.IngressBackend{
+										ServiceName: ServiceName(grafana),
+										ServicePort: intstr.FromString(grafanaPortName),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if spec.TLSSecretName != "" {
+		ing.Spec.TLS = []networkingv1beta1.IngressTLS{{Hosts: []string{spec.Host}, SecretName: spec.TLSSecretName}}
+	}
+
+	owner := metav1.NewControllerRef(
+		grafana, aimsv1.SchemeGroupVersion.
+			WithKind("Grafana"),
+	)
+	ing.ObjectMeta.OwnerReferences = append(ing.ObjectMeta.OwnerReferences, *owner)
+
+	return ing
+}
+
+// MergeIngress applies the rules and TLS of desired onto the live Ingress found and reports
+// whether anything changed. Annotations and a default backend set by others, e.g. by the
+// ingress controller, are kept.
+func MergeIngress(found, desired *networkingv1beta1.Ingress) (*networkingv1beta1.Ingress, bool) {
+	ing := found.DeepCopy()
+	mergeStringMap(&ing.Labels, desired.Labels)
+	for key, value := range desired.Annotations {
+		if ing.Annotations == nil {
+			ing.Annotations = map[string]string{}
+		}
+		ing.Annotations[key] = value
+	}
+	ing.Spec.Rules = desired.Spec.Rules
+	ing.Spec.TLS = desired.Spec.TLS
+	return ing, !equality.Semantic.DeepEqual(found, ing)
+}
+
+// Route returns the OpenShift Route for spec.ingress. A TLS secret is embedded into the
+// route as edge termination, since routes cannot reference secrets.
+func Route(grafana *aimsv1.Grafana, tlsSecret *v1.Secret) *unstructured.Unstructured {
+	spec := grafana.Spec.Ingress
+	routeSpec := map[string]interface{}{
+		"host": spec.Host,
+		"path": ingressPath(spec),
+		"to": map[string]interface{}{
+			"kind":   "Service",
+			"name":   ServiceName(grafana),
+			"weight": int64(100),
+		},
+		"port": map[string]interface{}{
+			"targetPort": grafanaPortName,
+		},
+		"wildcardPolicy": "None",
+	}
+	if tlsSecret != nil {
+		routeSpec["tls"] = map[string]interface{}{
+			"termination":                   "edge",
+			"insecureEdgeTerminationPolicy": "Redirect",
+			"certificate":                   string(tlsSecret.Data[v1.TLSCertKey]),
+			"key":                           string(tlsSecret.Data[v1.TLSPrivateKeyKey]),
+		}
+	}
+
+	route := &unstructured.Unstructured{Object: map[string]interface{}{"spec": routeSpec}}
+	route.SetAPIVersion(RouteGVR.GroupVersion().String())
+	route.SetKind("Route")
+	route.SetName(IngressName(grafana))
+	route.SetNamespace(grafana.Namespace)
+	route.SetLabels(Labels(grafana))
+	route.SetAnnotations(spec.Annotations)
+
+	owner := metav1.NewControllerRef(
+		grafana, aimsv1.SchemeGroupVersion.
+			WithKind("Grafana"),
+	)
+	route.SetOwnerReferences([]metav1.OwnerReference{*owner})
+
+	return route
+}
+
+// routeFields are the fields of the Route spec the operator manages, others such as
+// alternateBackends are left to the cluster and its users
+var routeFields = []string{"host", "path", "to", "port", "tls", "wildcardPolicy"}
+
+// MergeRoute applies the managed spec fields and the annotations of desired onto the live
+// Route found and reports whether anything changed.
+func MergeRoute(found, desired *unstructured.Unstructured) (*unstructured.Unstructured, bool) {
+	route := found.DeepCopy()
+	annotations := route.GetAnnotations()
+	for key, value := range desired.GetAnnotations() {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[key] = value
+	}
+	route.SetAnnotations(annotations)
+	labels := route.GetLabels()
+	mergeStringMap(&labels, desired.GetLabels())
+	route.SetLabels(labels)
+	spec, _ := route.Object["spec"].(map[string]interface{})
+	if spec == nil {
+		spec = map[string]interface{}{}
+	}
+	desiredSpec, _ := desired.Object["spec"].(map[string]interface{})
+	for _, field := range routeFields {
+		if value, ok := desiredSpec[field]; ok {
+			spec[field] = value
+		} else {
+			delete(spec, field)
+		}
+	}
+	route.Object["spec"] = spec
+	return route, !equality.Semantic.DeepEqual(found.Object, route.Object)
+}
@@ -0,0 +1,130 @@
+package util
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+)
+
+func TestMergeIngress(t *testing.T) {
+	grafana := &aimsv1.Grafana{
+		ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns"},
+		Spec:       aimsv1.GrafanaSpec{Ingress: &aimsv1.GrafanaIngress{Host: "grafana.example.com", TLSSecretName: "tls"}},
+	}
+	desired := Ingress(grafana)
+	backend := &networkingv1beta1.IngressBackend{ServiceName: "default", ServicePort: intstr.FromInt(80)}
+
+	tests := []struct {
+		name    string
+		found   func(*networkingv1beta1.Ingress)
+		changed bool
+	}{
+		{name: "unchanged", found: func(ing *networkingv1beta1.Ingress) {}},
+		{
+			name: "default backend and annotation of others",
+			found: func(ing *networkingv1beta1.Ingress) {
+				ing.Spec.Backend = backend
+				ing.Annotations = map[string]string{"other": "x"}
+			},
+		},
+		{name: "host changed", found: func(ing *networkingv1beta1.Ingress) { ing.Spec.Rules[0].Host = "other.example.com" }, changed: true},
+		{name: "tls removed", found: func(ing *networkingv1beta1.Ingress) { ing.Spec.TLS = nil }, changed: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			found := desired.DeepCopy()
+			test.found(found)
+			kept := found.DeepCopy()
+
+			merged, changed := MergeIngress(found, desired)
+			if changed != test.changed {
+				t.Errorf("expected changed %v, got %v", test.changed, changed)
+			}
+			if !equality.Semantic.DeepEqual(merged.Spec.Rules, desired.Spec.Rules) || !equality.Semantic.DeepEqual(merged.Spec.TLS, desired.Spec.TLS) {
+				t.Errorf("expected rules %v and tls %v, got %v and %v", desired.Spec.Rules, desired.Spec.TLS, merged.Spec.Rules, merged.Spec.TLS)
+			}
+			if !equality.Semantic.DeepEqual(merged.Spec.Backend, kept.Spec.Backend) {
+				t.Errorf("expected backend %v to be kept, got %v", kept.Spec.Backend, merged.Spec.Backend)
+			}
+			if kept.Annotations["other"] != merged.Annotations["other"] {
+				t.Errorf("expected annotations %v to be kept, got %v", kept.Annotations, merged.Annotations)
+			}
+		})
+	}
+}
+
+func TestMergeRoute(t *testing.T) {
+	grafana := &aimsv1.Grafana{
+		ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns"},
+		Spec:       aimsv1.GrafanaSpec{Ingress: &aimsv1.GrafanaIngress{Host: "grafana.example.com"}},
+	}
+	tlsSecret := &v1.Secret{Data: map[string][]byte{v1.TLSCertKey: []byte("cert"), v1.TLSPrivateKeyKey: []byte("key")}}
+	alternate := []interface{}{map[string]interface{}{"kind": "Service", "name": "canary", "weight": int64(10)}}
+
+	tests := []struct {
+		name     string
+		found    map[string]interface{}
+		desired  map[string]interface{}
+		changed  bool
+		expected map[string]interface{}
+	}{
+		{
+			name:     "unchanged",
+			found:    Route(grafana, nil).Object["spec"].(map[string]interface{}),
+			desired:  Route(grafana, nil).Object["spec"].(map[string]interface{}),
+			expected: Route(grafana, nil).Object["spec"].(map[string]interface{}),
+		},
+		{
+			name:     "alternate backends of others",
+			found:    withField(Route(grafana, nil).Object["spec"], "alternateBackends", alternate),
+			desired:  Route(grafana, nil).Object["spec"].(map[string]interface{}),
+			expected: withField(Route(grafana, nil).Object["spec"], "alternateBackends", alternate),
+		},
+		{
+			name:     "tls added",
+			found:    withField(Route(grafana, nil).Object["spec"], "alternateBackends", alternate),
+			desired:  Route(grafana, tlsSecret).Object["spec"].(map[string]interface{}),
+			changed:  true,
+			expected: withField(Route(grafana, tlsSecret).Object["spec"], "alternateBackends", alternate),
+		},
+		{
+			name:     "tls removed",
+			found:    Route(grafana, tlsSecret).Object["spec"].(map[string]interface{}),
+			desired:  Route(grafana, nil).Object["spec"].(map[string]interface{}),
+			changed:  true,
+			expected: Route(grafana, nil).Object["spec"].(map[string]interface{}),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			found, desired := Route(grafana, nil), Route(grafana, nil)
+			found.Object["spec"], desired.Object["spec"] = test.found, test.desired
+
+			merged, changed := MergeRoute(found, desired)
+			if changed != test.changed {
+				t.Errorf("expected changed %v, got %v", test.changed, changed)
+			}
+			if !equality.Semantic.DeepEqual(merged.Object["spec"], test.expected) {
+				t.Errorf("expected spec %v, got %v", test.expected, merged.Object["spec"])
+			}
+		})
+	}
+}
+
+// withField returns a copy of the route spec with field set to value
+func withField(spec interface{}, field string, value interface{}) map[string]interface{} {
+	copied := map[string]interface{}{}
+	for key, v := range spec.(map[string]interface{}) {
+		copied[key] = v
+	}
+	copied[field] = value
+	return copied
+}
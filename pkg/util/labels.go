@@ -0,0 +1,70 @@
+package util
+
+import (
+	"k8s.io/apimachinery/pkg/labels"
+
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+)
+
+// Standard labels set on every object the operator creates for an instance
+const (
+	nameLabel      string = "app.kubernetes.io/name"
+	instanceLabel  string = "app.kubernetes.io/instance"
+	managedByLabel string = "app.kubernetes.io/managed-by"
+
+	managedBy string = "grafana-operator"
+)
+
+// legacyConfigMaps were named without an instance prefix before instances could share a namespace
+var legacyConfigMaps = []string{"grafana-config", "grafana-datasources", "grafana-dashboards"}
+
+// Labels returns the labels of the objects created for the instance
+func Labels(grafana *aimsv1.Grafana) map[string]string {
+	return map[string]string{
+		nameLabel:      "grafana",
+		instanceLabel:  grafana.Name,
+		managedByLabel: managedBy,
+	}
+}
+
+// PodLabels returns the labels selecting the grafana pods of the instance
+func PodLabels(grafana *aimsv1.Grafana) map[string]string {
+	return map[string]string{
+		nameLabel:     "grafana",
+		instanceLabel: grafana.Name,
+	}
+}
+
+// ConfigMapName prefixes the name of a configmap with the instance name
+func ConfigMapName(grafana *aimsv1.Grafana, name string) string {
+	return grafana.Name + "-" + name
+}
+
+// LegacyConfigMapNames returns the fixed configmap names used by earlier versions
+func LegacyConfigMapNames() []string {
+	names := append([]string{}, legacyConfigMaps...)
+	for _, bundle := range DashboardBundleNames() {
+		names = append(names, bundle+"-dashboards")
+	}
+	return names
+}
+
+// SetLabels adds the labels of the instance to l and reports whether any was missing
+func SetLabels(grafana *aimsv1.Grafana, l *map[string]string) bool {
+	return mergeStringMap(l, Labels(grafana))
+}
+
+// HasLabels reports whether l carries all labels of the instance
+func HasLabels(grafana *aimsv1.Grafana, l map[string]string) bool {
+	for key, value := range Labels(grafana) {
+		if l[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Selector selects the objects labeled for the instance
+func Selector(grafana *aimsv1.Grafana) labels.Selector {
+	return labels.SelectorFromSet(Labels(grafana))
+}
@@ -0,0 +1,111 @@
+package util
+
+import (
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const grafanaPortName string = "grafana"
+
+// ServiceName returns the name of the Service exposing the instance
+func ServiceName(grafana *aimsv1.Grafana) string {
+	return grafana.Name + "-grafana"
+}
+
+// Service returns the Service exposing the grafana pods of the instance
+func Service(grafana *aimsv1.Grafana) *v1.Service {
+	spec := grafana.Spec.Service
+	if spec == nil {
+		spec = &aimsv1.GrafanaService{}
+	}
+
+	serviceType := spec.Type
+	if serviceType == "" {
+		serviceType = v1.ServiceTypeClusterIP
+	}
+	port := spec.Port
+	if port == 0 {
+		port = GrafanaPort
+	}
+	affinity := spec.SessionAffinity
+	if affinity == "" {
+		affinity = v1.ServiceAffinityNone
+	}
+
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        ServiceName(grafana),
+			Namespace:   grafana.Namespace,
+			Labels:      Labels(grafana),
+			Annotations: spec.Annotations,
+		},
+		Spec: v1.ServiceSpec{
+			Type:     serviceType,
+			Selector: PodLabels(grafana),
+			Ports: []v1.ServicePort{
+				{
+					Name:       grafanaPortName,
+					Protocol:   v1.ProtocolTCP,
+					Port:       port,
+					TargetPort: intstr.FromInt(int(GrafanaPort)),
+				},
+			},
+			SessionAffinity: affinity,
+		},
+	}
+	if serviceType == v1.ServiceTypeNodePort || serviceType == v1.ServiceTypeLoadBalancer {
+		svc.Spec.Ports[0].NodePort = spec.NodePort
+	}
+
+	owner := metav1.NewControllerRef(
+		grafana, aimsv1.SchemeGroupVersion.
+			WithKind("Grafana"),
+	)
+	svc.ObjectMeta.OwnerReferences = append(svc.ObjectMeta.OwnerReferences, *owner)
+
+	return svc
+}
+
+// MergeService applies the managed fields of desired onto the live Service found, keeping
+// the fields allocated by the API server, and reports whether anything changed. Annotations
+// set by others are kept, those of spec.service are enforced.
+func MergeService(found, desired *v1.Service) (*v1.Service, bool) {
+	svc := found.DeepCopy()
+	mergeStringMap(&svc.Labels, desired.Labels)
+
+	for key, value := range desired.Annotations {
+		if svc.Annotations == nil {
+			svc.Annotations = map[string]string{}
+		}
+		svc.Annotations[key] = value
+	}
+
+	svc.Spec.Type = desired.Spec.Type
+	if desired.Spec.Type == v1.ServiceTypeClusterIP {
+		svc.Spec.ExternalTrafficPolicy = ""
+		svc.Spec.HealthCheckNodePort = 0
+	}
+	svc.Spec.Selector = desired.Spec.Selector
+	svc.Spec.SessionAffinity = desired.Spec.SessionAffinity
+	if desired.Spec.SessionAffinity == v1.ServiceAffinityNone {
+		svc.Spec.SessionAffinityConfig = nil
+	}
+
+	ports := desired.Spec.Ports
+	for i := range ports {
+		// Keep an allocated node port unless a fixed one is requested
+		if ports[i].NodePort == 0 && desired.Spec.Type != v1.ServiceTypeClusterIP {
+			for _, current := range found.Spec.Ports {
+				if current.Name == ports[i].Name {
+					ports[i].NodePort = current.NodePort
+				}
+			}
+		}
+	}
+	svc.Spec.Ports = ports
+
+	return svc, !equality.Semantic.DeepEqual(found, svc)
+}
@@ -0,0 +1,111 @@
+package util
+
+import (
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	dataVolume         string = "grafana-data"
+	defaultStorageSize string = "10Gi"
+
+	// grafanaGroupID is the group of the grafana user in the official image, given to
+	// claims through fsGroup so the data directory is writable
+	grafanaGroupID int64 = 472
+)
+
+// DataClaimName returns the name of the claim mounted at /var/lib/grafana
+func DataClaimName(grafana *aimsv1.Grafana) string {
+	if grafana.Spec.Storage != nil && grafana.Spec.Storage.ExistingClaim != "" {
+		return grafana.Spec.Storage.ExistingClaim
+	}
+	return grafana.Name + "-grafana-data"
+}
+
+// ManagesDataClaim reports whether the operator creates the data claim of the instance
+func ManagesDataClaim(grafana *aimsv1.Grafana) bool {
+	return grafana.Spec.Storage != nil && grafana.Spec.Storage.ExistingClaim == ""
+}
+
+// RetainsDataClaim reports whether the data claim outlives the Grafana resource
+func RetainsDataClaim(grafana *aimsv1.Grafana) bool {
+	return grafana.Spec.Storage != nil && grafana.Spec.Storage.RetainPolicy == aimsv1.StorageRetainPolicyRetain
+}
+
+// DataClaim returns the claim the operator creates for the instance. It is owned by the
+// instance, and garbage collected with it, unless the retain policy is Retain.
+func DataClaim(grafana *aimsv1.Grafana) *v1.PersistentVolumeClaim {
+	storage := grafana.Spec.Storage
+	size := storage.Size
+	if size.IsZero() {
+		size = resource.MustParse(defaultStorageSize)
+	}
+
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DataClaimName(grafana),
+			Namespace: grafana.Namespace,
+			Labels:    Labels(grafana),
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes:      storageAccessModes(grafana),
+			StorageClassName: storage.StorageClassName,
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceStorage: size},
+			},
+		},
+	}
+
+	if !RetainsDataClaim(grafana) {
+		owner := metav1.NewControllerRef(
+			grafana, aimsv1.SchemeGroupVersion.
+				WithKind("Grafana"),
+		)
+		pvc.ObjectMeta.OwnerReferences = append(pvc.ObjectMeta.OwnerReferences, *owner)
+	}
+
+	return pvc
+}
+
+func storageAccessModes(grafana *aimsv1.Grafana) []v1.PersistentVolumeAccessMode {
+	if len(grafana.Spec.Storage.AccessModes) == 0 {
+		return []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce}
+	}
+	return grafana.Spec.Storage.AccessModes
+}
+
+// dataVolumeSource returns the claim of the instance, or an EmptyDir without spec.storage
+func dataVolumeSource(grafana *aimsv1.Grafana) v1.VolumeSource {
+	if grafana.Spec.Storage == nil {
+		return v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}
+	}
+	return v1.VolumeSource{
+		PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: DataClaimName(grafana)},
+	}
+}
+
+// podSecurityContext lets the grafana user write to a mounted claim
+func podSecurityContext(grafana *aimsv1.Grafana) *v1.PodSecurityContext {
+	if grafana.Spec.Storage == nil {
+		return nil
+	}
+	fsGroup := grafanaGroupID
+	return &v1.PodSecurityContext{FSGroup: &fsGroup}
+}
+
+// deploymentStrategy recreates the pods when the claim cannot be mounted by the old and the
+// new pods at once, which is the case for any claim that is not ReadWriteMany.
+func deploymentStrategy(grafana *aimsv1.Grafana) appsv1.DeploymentStrategy {
+	if grafana.Spec.Storage == nil {
+		return appsv1.DeploymentStrategy{Type: appsv1.RollingUpdateDeploymentStrategyType}
+	}
+	for _, mode := range storageAccessModes(grafana) {
+		if mode == v1.ReadWriteMany {
+			return appsv1.DeploymentStrategy{Type: appsv1.RollingUpdateDeploymentStrategyType}
+		}
+	}
+	return appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+}
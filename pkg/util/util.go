@@ -1,8 +1,12 @@
 package util
 
 import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
 	"io/ioutil"
-	"os"
+	"path/filepath"
 	"strings"
 	"text/template"
 
@@ -14,20 +18,24 @@ import (
 	"k8s.io/klog"
 )
 
-var configPath = map[string]string{
-	"grafana-dashboards": "dashboards.yaml",
-	"kafka-dashboards":   "strimzi-kafka.json,strimzi-zookeeper.json,strimzi-kafka-exporter.json",
-}
-
 var configTmplPath = map[string]string{
-	"grafana-config":      "grafana.ini",
 	"grafana-datasources": "datasources.yaml",
 }
 
-func buildCMData(path string) map[string]string {
+// GrafanaPort is the port the grafana container listens on
+const GrafanaPort int32 = 3000
+
+const (
+	defaultUserKey     string = "user"
+	defaultPasswordKey string = "password"
+	secretKeyKey       string = "secret_key"
+	defaultAdminUser   string = "admin"
+)
+
+func buildCMData(paths []string) map[string]string {
 	m := make(map[string]string)
 
-	for _, path := range strings.Split(path, ",") {
+	for _, path := range paths {
 		data, err := ioutil.ReadFile(config.DashboardPath + path)
 		if err != nil {
 			klog.Errorf("Couldn't read file: %s %s", path, err)
@@ -38,114 +46,123 @@ func buildCMData(path string) map[string]string {
 	return m
 }
 
+var templateFuncs = template.FuncMap{
+	// json renders a value as JSON, which is also valid inline YAML
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
 func buildCMDataFromTemplate(path string, cfg *config.GrafanaConfig) map[string]string {
 	m := make(map[string]string)
 
-	tmpfile, err := ioutil.TempFile("/tmp", ".grafana-controller")
-	if err != nil {
-		klog.Errorf("Unable to create temporary file: %s : %s", path, err)
+	for _, path := range strings.Split(path, ",") {
+		m[path] = renderTemplate(path, cfg)
 	}
 
-	for _, path := range strings.Split(path, ",") {
-		tmpl, err := template.ParseFiles(config.TemplatePath + path + ".tmpl")
-		if err != nil {
-			klog.Errorf("Unable to parse template file: %s: %s", path+".tmpl", err)
-		}
-		tmpl.Execute(tmpfile, cfg)
-		tmpfile.Sync()
-		tmpfile.Close()
-		defer os.Remove(tmpfile.Name())
+	return m
+}
 
-		data, err := ioutil.ReadFile(tmpfile.Name())
-		if err != nil {
-			klog.Errorf("Unable to generate config from template file: %s : %s", path, err)
-		}
-		m[path] = string(data)
+func renderTemplate(path string, cfg *config.GrafanaConfig) string {
+	file := config.TemplatePath + path + ".tmpl"
+	tmpl, err := template.New(filepath.Base(file)).Funcs(templateFuncs).ParseFiles(file)
+	if err != nil {
+		klog.Errorf("Unable to parse template file: %s: %s", path+".tmpl", err)
+		return ""
 	}
 
-	return m
+	var data bytes.Buffer
+	if err = tmpl.Execute(&data, cfg); err != nil {
+		klog.Errorf("Unable to generate config from template file: %s : %s", path, err)
+	}
+	return data.String()
 }
 
 // CreateConfigMap returns configmaplist for loading to grafana deployment
-func CreateConfigMap(grafana *aimsv1.Grafana, cmList *v1.ConfigMapList) *v1.ConfigMapList {
+func CreateConfigMap(grafana *aimsv1.Grafana, folders DashboardFolders, external []aimsv1.GrafanaDatasource, cmList *v1.ConfigMapList) (*v1.ConfigMapList, error) {
 
+	// Datasources carrying secure data are provisioned from the datasource secret instead
 	gcfg := &config.GrafanaConfig{
-		AdminPassword: grafana.Spec.Password,
-		AdminUser:     grafana.Spec.Username,
-		PrometheusURL: grafana.Spec.PrometheusURL,
+		Datasources: filterDatasources(Datasources(grafana, external, nil), false),
 	}
 
-	cmItems := []v1.ConfigMap{}
+	grafanaINI, err := GrafanaINI(grafana)
+	if err != nil {
+		return nil, err
+	}
 
-	for configName, path := range configPath {
-		data := buildCMData(path)
-		cm := &v1.ConfigMap{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      configName,
-				Namespace: grafana.Namespace,
-			},
-			Data: data,
-		}
+	cmItems := []v1.ConfigMap{}
+	cmItems = append(cmItems, ownedConfigMap(grafana, ConfigMapName(grafana, "grafana-config"), map[string]string{grafanaINIKey: grafanaINI}))
 
-		owner := metav1.NewControllerRef(
-			grafana, aimsv1.SchemeGroupVersion.
-				WithKind("Grafana"),
-		)
-		cm.ObjectMeta.OwnerReferences = append(cm.ObjectMeta.OwnerReferences, *owner)
-		cmItems = append(cmItems, *cm)
-	}
+	cmItems = append(cmItems, dashboardBundleConfigMaps(grafana)...)
 
 	for configTemplate, path := range configTmplPath {
-		data := buildCMDataFromTemplate(path, gcfg)
-		cm := &v1.ConfigMap{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      configTemplate,
-				Namespace: grafana.Namespace,
-			},
-			Data: data,
-		}
-
-		owner := metav1.NewControllerRef(
-			grafana, aimsv1.SchemeGroupVersion.
-				WithKind("Grafana"),
-		)
-		cm.ObjectMeta.OwnerReferences = append(cm.ObjectMeta.OwnerReferences, *owner)
-		cmItems = append(cmItems, *cm)
+		cmItems = append(cmItems, ownedConfigMap(grafana, ConfigMapName(grafana, configTemplate), buildCMDataFromTemplate(path, gcfg)))
 	}
 
+	// The provider file gets one provider per folder of the attached GrafanaDashboards
+	providers := map[string]string{"dashboards.yaml": dashboardProvidersConfig(folders)}
+	cmItems = append(cmItems, ownedConfigMap(grafana, ConfigMapName(grafana, "grafana-dashboards"), providers))
+
 	cmList.Items = cmItems
-	return cmList
+	return cmList, nil
 
 }
 
-// Deployment creates grafana pod
-func Deployment(grafana *aimsv1.Grafana) *appsv1.Deployment {
+func ownedConfigMap(grafana *aimsv1.Grafana, name string, data map[string]string) v1.ConfigMap {
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: grafana.Namespace,
+			Labels:    Labels(grafana),
+		},
+		Data: data,
+	}
+
+	owner := metav1.NewControllerRef(
+		grafana, aimsv1.SchemeGroupVersion.
+			WithKind("Grafana"),
+	)
+	cm.ObjectMeta.OwnerReferences = append(cm.ObjectMeta.OwnerReferences, *owner)
+	return *cm
+}
+
+// Deployment creates grafana pod, mounting the attached GrafanaDashboards by folder
+func Deployment(grafana *aimsv1.Grafana, folders DashboardFolders) *appsv1.Deployment {
+	optional := true
 	deploy := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      grafana.Name + "-grafana",
 			Namespace: grafana.Namespace,
+			Labels:    Labels(grafana),
 		},
 		Spec: appsv1.DeploymentSpec{
+			Replicas: grafana.Spec.Replicas,
 			Selector: &metav1.LabelSelector{
-				MatchLabels: map[string]string{"app": "grafana"},
+				MatchLabels: PodLabels(grafana),
 			},
+			Strategy: deploymentStrategy(grafana),
 			Template: v1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "grafana"}},
+				ObjectMeta: metav1.ObjectMeta{Labels: Labels(grafana)},
 				Spec: v1.PodSpec{
 					Containers: []v1.Container{
 						{
-							Name:  grafana.Name + "-grafana",
-							Image: grafana.Spec.Image,
-							Ports: []v1.ContainerPort{{ContainerPort: 3000}},
+							Name:      grafana.Name + "-grafana",
+							Image:     grafana.Spec.Image,
+							Ports:     []v1.ContainerPort{{Name: grafanaPortName, ContainerPort: GrafanaPort}},
+							Env:       append(AdminCredentialsEnv(grafana), DatabaseEnv(grafana)...),
+							Resources: grafana.Spec.Resources,
 							VolumeMounts: []v1.VolumeMount{
 								{Name: "grafana-config", MountPath: "/etc/grafana"},
-								{Name: "grafana-data", MountPath: "/var/lib/grafana"},
+								{Name: dataVolume, MountPath: "/var/lib/grafana"},
 								{Name: "grafana-datasources", MountPath: "/etc/grafana/provisioning/datasources"},
 								{Name: "grafana-dashboards", MountPath: "/etc/grafana/provisioning/dashboards"},
-								{Name: "kafka-dashboards", MountPath: "/grafana-dashboard-definitions/0"},
+								{Name: builtinDashboardsVolume, MountPath: builtinDashboardsPath},
 							},
 						},
 					},
+					SecurityContext: podSecurityContext(grafana),
 					ImagePullSecrets: []v1.LocalObjectReference{
 						{
 							Name: "intps-kafka-svc-pull-secret",
@@ -157,23 +174,35 @@ func Deployment(grafana *aimsv1.Grafana) *appsv1.Deployment {
 							VolumeSource: v1.VolumeSource{
 								ConfigMap: &v1.ConfigMapVolumeSource{
 									LocalObjectReference: v1.LocalObjectReference{
-										Name: "grafana-config",
+										Name: ConfigMapName(grafana, "grafana-config"),
 									},
 								},
 							},
 						},
 						{
-							Name: "grafana-data",
-							VolumeSource: v1.VolumeSource{
-								EmptyDir: &v1.EmptyDirVolumeSource{},
-							},
+							Name:         dataVolume,
+							VolumeSource: dataVolumeSource(grafana),
 						},
 						{
 							Name: "grafana-datasources",
 							VolumeSource: v1.VolumeSource{
-								ConfigMap: &v1.ConfigMapVolumeSource{
-									LocalObjectReference: v1.LocalObjectReference{
-										Name: "grafana-datasources",
+								Projected: &v1.ProjectedVolumeSource{
+									Sources: []v1.VolumeProjection{
+										{
+											ConfigMap: &v1.ConfigMapProjection{
+												LocalObjectReference: v1.LocalObjectReference{
+													Name: ConfigMapName(grafana, "grafana-datasources"),
+												},
+											},
+										},
+										{
+											Secret: &v1.SecretProjection{
+												LocalObjectReference: v1.LocalObjectReference{
+													Name: DatasourceSecretName(grafana),
+												},
+												Optional: &optional,
+											},
+										},
 									},
 								},
 							},
@@ -183,20 +212,14 @@ func Deployment(grafana *aimsv1.Grafana) *appsv1.Deployment {
 							VolumeSource: v1.VolumeSource{
 								ConfigMap: &v1.ConfigMapVolumeSource{
 									LocalObjectReference: v1.LocalObjectReference{
-										Name: "grafana-dashboards",
+										Name: ConfigMapName(grafana, "grafana-dashboards"),
 									},
 								},
 							},
 						},
 						{
-							Name: "kafka-dashboards",
-							VolumeSource: v1.VolumeSource{
-								ConfigMap: &v1.ConfigMapVolumeSource{
-									LocalObjectReference: v1.LocalObjectReference{
-										Name: "kafka-dashboards",
-									},
-								},
-							},
+							Name:         builtinDashboardsVolume,
+							VolumeSource: builtinDashboardsVolumeSource(grafana),
 						},
 					},
 				},
@@ -204,6 +227,11 @@ func Deployment(grafana *aimsv1.Grafana) *appsv1.Deployment {
 		},
 	}
 
+	volumes, mounts := customDashboardVolumes(grafana, folders)
+	pod := &deploy.Spec.Template.Spec
+	pod.Volumes = append(pod.Volumes, volumes...)
+	pod.Containers[0].VolumeMounts = append(pod.Containers[0].VolumeMounts, mounts...)
+
 	owner := metav1.NewControllerRef(
 		grafana, aimsv1.SchemeGroupVersion.
 			WithKind("Grafana"),
@@ -212,3 +240,179 @@ func Deployment(grafana *aimsv1.Grafana) *appsv1.Deployment {
 
 	return deploy
 }
+
+// GeneratesAdminSecret reports whether the operator owns the admin credentials because
+// the spec provides neither a credentials secret nor a password.
+func GeneratesAdminSecret(grafana *aimsv1.Grafana) bool {
+	return grafana.Spec.CredentialsSecretRef == nil && grafana.Spec.Password == ""
+}
+
+// ManagesAdminSecret reports whether the admin credentials are provided through the operator
+// managed admin secret, generated or holding the inline spec.password, rather than through
+// credentialsSecretRef. Credentials never go to the grafana.ini configmap.
+func ManagesAdminSecret(grafana *aimsv1.Grafana) bool {
+	return grafana.Spec.CredentialsSecretRef == nil
+}
+
+// AdminSecretName returns the name of the operator managed admin credentials secret
+func AdminSecretName(grafana *aimsv1.Grafana) string {
+	return grafana.Name + "-grafana-admin"
+}
+
+// AdminSecret returns the admin credentials secret of the instance. It holds the inline
+// spec.password when set, and otherwise a random admin password and secret_key.
+func AdminSecret(grafana *aimsv1.Grafana) (*v1.Secret, error) {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      AdminSecretName(grafana),
+			Namespace: grafana.Namespace,
+			Labels:    Labels(grafana),
+		},
+		Type: v1.SecretTypeOpaque,
+		Data: map[string][]byte{},
+	}
+	SetAdminCredentials(grafana, secret)
+
+	if GeneratesAdminSecret(grafana) {
+		password, err := RandomString(24)
+		if err != nil {
+			return nil, err
+		}
+		secretKey, err := RandomString(32)
+		if err != nil {
+			return nil, err
+		}
+		secret.Data[defaultPasswordKey] = []byte(password)
+		secret.Data[secretKeyKey] = []byte(secretKey)
+	}
+
+	owner := metav1.NewControllerRef(
+		grafana, aimsv1.SchemeGroupVersion.
+			WithKind("Grafana"),
+	)
+	secret.ObjectMeta.OwnerReferences = append(secret.ObjectMeta.OwnerReferences, *owner)
+
+	return secret, nil
+}
+
+// SetAdminCredentials sets the admin user and the inline spec.password of the instance in
+// its admin secret. It reports whether the secret changed. A generated password is kept.
+func SetAdminCredentials(grafana *aimsv1.Grafana, secret *v1.Secret) bool {
+	user := grafana.Spec.Username
+	if user == "" {
+		user = defaultAdminUser
+	}
+
+	desired := map[string]string{defaultUserKey: user}
+	if grafana.Spec.Password != "" {
+		desired[defaultPasswordKey] = grafana.Spec.Password
+	}
+
+	changed := false
+	for key, value := range desired {
+		if string(secret.Data[key]) != value {
+			if secret.Data == nil {
+				secret.Data = map[string][]byte{}
+			}
+			secret.Data[key] = []byte(value)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// RandomString returns n random bytes encoded as url safe base64
+func RandomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CredentialsRef returns the secret reference the admin credentials are read from, which is
+// either the user supplied credentialsSecretRef or the operator generated admin secret.
+func CredentialsRef(grafana *aimsv1.Grafana) *aimsv1.CredentialsSecretRef {
+	if grafana.Spec.CredentialsSecretRef != nil {
+		return grafana.Spec.CredentialsSecretRef
+	}
+	if ManagesAdminSecret(grafana) {
+		return &aimsv1.CredentialsSecretRef{Name: AdminSecretName(grafana)}
+	}
+	return nil
+}
+
+// CredentialsKeys returns the user and password keys of the credentials secret, applying defaults
+func CredentialsKeys(ref *aimsv1.CredentialsSecretRef) (string, string) {
+	userKey, passwordKey := ref.UserKey, ref.PasswordKey
+	if userKey == "" {
+		userKey = defaultUserKey
+	}
+	if passwordKey == "" {
+		passwordKey = defaultPasswordKey
+	}
+	return userKey, passwordKey
+}
+
+// AdminCredentialsEnv returns the GF_SECURITY_* env vars sourced from the credentials secret
+func AdminCredentialsEnv(grafana *aimsv1.Grafana) []v1.EnvVar {
+	ref := CredentialsRef(grafana)
+	if ref == nil {
+		return nil
+	}
+
+	userKey, passwordKey := CredentialsKeys(ref)
+	env := []v1.EnvVar{
+		secretEnv("GF_SECURITY_ADMIN_USER", ref.Name, userKey),
+		secretEnv("GF_SECURITY_ADMIN_PASSWORD", ref.Name, passwordKey),
+	}
+	if GeneratesAdminSecret(grafana) {
+		env = append(env, secretEnv("GF_SECURITY_SECRET_KEY", ref.Name, secretKeyKey))
+	}
+	return env
+}
+
+// ReferencesSecret reports whether the grafana instance consumes the named secret
+func ReferencesSecret(grafana *aimsv1.Grafana, name string) bool {
+	ref := CredentialsRef(grafana)
+	if ref != nil && ref.Name == name {
+		return true
+	}
+
+	if ing := grafana.Spec.Ingress; ing != nil && ing.TLSSecretName == name {
+		return true
+	}
+
+	if db := grafana.Spec.Database; db != nil && db.PasswordSecretRef != nil && db.PasswordSecretRef.Name == name {
+		return true
+	}
+
+	for _, ds := range grafana.Spec.Datasources {
+		if DatasourceReferencesSecret(ds, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// DatasourceReferencesSecret reports whether a datasource reads secure data from the named secret
+func DatasourceReferencesSecret(ds aimsv1.GrafanaDatasource, name string) bool {
+	for _, source := range ds.SecureJSONDataFrom {
+		if source.SecretKeyRef != nil && source.SecretKeyRef.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func secretEnv(name, secret, key string) v1.EnvVar {
+	return v1.EnvVar{
+		Name: name,
+		ValueFrom: &v1.EnvVarSource{
+			SecretKeyRef: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: secret},
+				Key:                  key,
+			},
+		},
+	}
+}
@@ -0,0 +1,148 @@
+// Package validation checks Grafana resources. The controller runs it before rolling out a
+// spec and the admission webhook runs it to reject the spec up front.
+package validation
+
+import (
+	"net/url"
+	"strings"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+)
+
+// forbiddenConfigKeys are credentials that must come from secrets rather than spec.config
+var forbiddenConfigKeys = map[string][]string{
+	"security": {"admin_user", "admin_password", "secret_key"},
+	"database": {"password", "url"},
+	"smtp":     {"password"},
+}
+
+// Validate checks the spec of a Grafana resource
+func Validate(grafana *aimsv1.Grafana) field.ErrorList {
+	spec := field.NewPath("spec")
+	errs := field.ErrorList{}
+	errs = append(errs, ValidateImage(grafana.Spec.Image, spec.Child("image"))...)
+	errs = append(errs, validateURL(grafana.Spec.PrometheusURL, spec.Child("prometheus_url"))...)
+	errs = append(errs, ValidateReplicas(grafana)...)
+	errs = append(errs, validateCredentials(grafana)...)
+	errs = append(errs, ValidateConfig(grafana.Spec.Config)...)
+	errs = append(errs, validateDatasources(grafana.Spec.Datasources, spec.Child("datasources"))...)
+	return errs
+}
+
+// ValidateUpdate checks a change of the spec of a Grafana resource, on top of Validate
+func ValidateUpdate(grafana, old *aimsv1.Grafana) field.ErrorList {
+	errs := Validate(grafana)
+	errs = append(errs, validateStorageUpdate(grafana.Spec.Storage, old.Spec.Storage, field.NewPath("spec", "storage"))...)
+	return errs
+}
+
+// ValidateImage requires an explicit tag or digest, an untagged image silently follows latest.
+// An unset image is defaulted on admission, it is only left empty without the defaulting webhook.
+func ValidateImage(image string, path *field.Path) field.ErrorList {
+	if image == "" {
+		return field.ErrorList{field.Required(path, "set it or register the defaulting webhook")}
+	}
+	name := image[strings.LastIndex(image, "/")+1:]
+	if !strings.Contains(name, ":") && !strings.Contains(name, "@") {
+		return field.ErrorList{field.Invalid(path, image, "must carry a tag or a digest")}
+	}
+	return nil
+}
+
+// ValidateReplicas rejects running several replicas on their own sqlite databases
+func ValidateReplicas(grafana *aimsv1.Grafana) field.ErrorList {
+	if grafana.Spec.Replicas != nil && *grafana.Spec.Replicas > 1 && grafana.Spec.Database == nil {
+		return field.ErrorList{field.Invalid(field.NewPath("spec", "replicas"), *grafana.Spec.Replicas, "more than 1 replica requires spec.database")}
+	}
+	return nil
+}
+
+// ValidateConfig rejects spec.config keys that would store credentials in plaintext
+func ValidateConfig(cfg map[string]map[string]string) field.ErrorList {
+	errs := field.ErrorList{}
+	for section, keys := range forbiddenConfigKeys {
+		for _, key := range keys {
+			if _, ok := cfg[section][key]; ok {
+				errs = append(errs, field.Forbidden(field.NewPath("spec", "config", section, key), "use a secret instead"))
+			}
+		}
+	}
+	return errs
+}
+
+// validateCredentials rejects blank passwords and secret references that name no secret.
+// An empty password asks the operator to generate one.
+func validateCredentials(grafana *aimsv1.Grafana) field.ErrorList {
+	spec := field.NewPath("spec")
+	errs := field.ErrorList{}
+	if grafana.Spec.Password != "" && strings.TrimSpace(grafana.Spec.Password) == "" {
+		errs = append(errs, field.Invalid(spec.Child("password"), "", "must not be blank, leave it empty to generate one"))
+	}
+	if ref := grafana.Spec.CredentialsSecretRef; ref != nil && ref.Name == "" {
+		errs = append(errs, field.Required(spec.Child("credentialsSecretRef", "name"), ""))
+	}
+	if db := grafana.Spec.Database; db != nil && db.PasswordSecretRef != nil {
+		path := spec.Child("database", "passwordSecretRef")
+		if db.PasswordSecretRef.Name == "" {
+			errs = append(errs, field.Required(path.Child("name"), ""))
+		}
+		if db.PasswordSecretRef.Key == "" {
+			errs = append(errs, field.Required(path.Child("key"), ""))
+		}
+	}
+	return errs
+}
+
+// validateDatasources allows a single default datasource, grafana rejects the whole provisioning
+// file otherwise
+func validateDatasources(datasources []aimsv1.GrafanaDatasource, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	defaults := 0
+	for i, ds := range datasources {
+		if !ds.IsDefault {
+			continue
+		}
+		if defaults++; defaults > 1 {
+			errs = append(errs, field.Invalid(path.Index(i).Child("isDefault"), true, "only one datasource may be the default"))
+		}
+	}
+	return errs
+}
+
+// validateURL requires an absolute http or https url
+func validateURL(value string, path *field.Path) field.ErrorList {
+	if value == "" {
+		return nil
+	}
+	u, err := url.Parse(value)
+	if err != nil {
+		return field.ErrorList{field.Invalid(path, value, err.Error())}
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return field.ErrorList{field.Invalid(path, value, "must be an absolute http or https url")}
+	}
+	return nil
+}
+
+// validateStorageUpdate rejects the changes a created claim cannot follow: its storage class
+// and access modes are immutable and it cannot shrink
+func validateStorageUpdate(storage, old *aimsv1.GrafanaStorage, path *field.Path) field.ErrorList {
+	if storage == nil || old == nil || storage.ExistingClaim != "" || old.ExistingClaim != "" {
+		return nil
+	}
+
+	errs := field.ErrorList{}
+	if !apiequality.Semantic.DeepEqual(storage.StorageClassName, old.StorageClassName) {
+		errs = append(errs, field.Forbidden(path.Child("storageClassName"), "is immutable once the claim is created"))
+	}
+	if !apiequality.Semantic.DeepEqual(storage.AccessModes, old.AccessModes) {
+		errs = append(errs, field.Forbidden(path.Child("accessModes"), "are immutable once the claim is created"))
+	}
+	if !storage.Size.IsZero() && storage.Size.Cmp(old.Size) < 0 {
+		errs = append(errs, field.Forbidden(path.Child("size"), "claims can only grow"))
+	}
+	return errs
+}
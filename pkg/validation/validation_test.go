@@ -0,0 +1,237 @@
+package validation
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+)
+
+// fields returns the type and path of errs, sorted so that map iteration order does not matter
+func fields(errs field.ErrorList) []string {
+	result := []string{}
+	for _, err := range errs {
+		result = append(result, string(err.Type)+" "+err.Field)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func TestValidateImage(t *testing.T) {
+	tests := []struct {
+		image    string
+		expected []string
+	}{
+		{image: "", expected: []string{"FieldValueRequired spec.image"}},
+		{image: "grafana/grafana:6.7.3", expected: []string{}},
+		{image: "grafana/grafana@sha256:0123456789abcdef", expected: []string{}},
+		{image: "grafana/grafana:6.7.3@sha256:0123456789abcdef", expected: []string{}},
+		{image: "registry:5000/grafana:7.0", expected: []string{}},
+		{image: "grafana/grafana", expected: []string{"FieldValueInvalid spec.image"}},
+		{image: "registry:5000/grafana", expected: []string{"FieldValueInvalid spec.image"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.image, func(t *testing.T) {
+			errs := fields(ValidateImage(test.image, field.NewPath("spec", "image")))
+			if !reflect.DeepEqual(errs, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, errs)
+			}
+		})
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   map[string]map[string]string
+		expected []string
+	}{
+		{name: "empty", expected: []string{}},
+		{
+			name:     "allowed keys",
+			config:   map[string]map[string]string{"auth": {"disable_login_form": "true"}, "database": {"type": "postgres"}},
+			expected: []string{},
+		},
+		{
+			name:     "admin password",
+			config:   map[string]map[string]string{"security": {"admin_password": "secret", "cookie_secure": "true"}},
+			expected: []string{"FieldValueForbidden spec.config.security.admin_password"},
+		},
+		{
+			name: "credentials of several sections",
+			config: map[string]map[string]string{
+				"database": {"password": "secret", "url": "postgres://user:secret@db/grafana"},
+				"smtp":     {"password": "secret"},
+			},
+			expected: []string{
+				"FieldValueForbidden spec.config.database.password",
+				"FieldValueForbidden spec.config.database.url",
+				"FieldValueForbidden spec.config.smtp.password",
+			},
+		},
+		{
+			name:     "empty value is still forbidden",
+			config:   map[string]map[string]string{"security": {"secret_key": ""}},
+			expected: []string{"FieldValueForbidden spec.config.security.secret_key"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := fields(ValidateConfig(test.config))
+			if !reflect.DeepEqual(errs, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, errs)
+			}
+		})
+	}
+}
+
+func TestValidateStorageUpdate(t *testing.T) {
+	standard, fast := "standard", "fast"
+	rwo := []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce}
+	rwx := []v1.PersistentVolumeAccessMode{v1.ReadWriteMany}
+	old := &aimsv1.GrafanaStorage{Size: resource.MustParse("10Gi"), StorageClassName: &standard, AccessModes: rwo}
+
+	tests := []struct {
+		name     string
+		storage  *aimsv1.GrafanaStorage
+		old      *aimsv1.GrafanaStorage
+		expected []string
+	}{
+		{name: "unchanged", storage: old.DeepCopy(), old: old, expected: []string{}},
+		{
+			name:     "grow",
+			storage:  &aimsv1.GrafanaStorage{Size: resource.MustParse("20Gi"), StorageClassName: &standard, AccessModes: rwo},
+			old:      old,
+			expected: []string{},
+		},
+		{
+			name:     "same size in another unit",
+			storage:  &aimsv1.GrafanaStorage{Size: resource.MustParse("10240Mi"), StorageClassName: &standard, AccessModes: rwo},
+			old:      old,
+			expected: []string{},
+		},
+		{
+			name:     "unset size",
+			storage:  &aimsv1.GrafanaStorage{StorageClassName: &standard, AccessModes: rwo},
+			old:      old,
+			expected: []string{},
+		},
+		{
+			name:     "shrink",
+			storage:  &aimsv1.GrafanaStorage{Size: resource.MustParse("5Gi"), StorageClassName: &standard, AccessModes: rwo},
+			old:      old,
+			expected: []string{"FieldValueForbidden spec.storage.size"},
+		},
+		{
+			name:     "storage class and access modes",
+			storage:  &aimsv1.GrafanaStorage{Size: resource.MustParse("10Gi"), StorageClassName: &fast, AccessModes: rwx},
+			old:      old,
+			expected: []string{"FieldValueForbidden spec.storage.accessModes", "FieldValueForbidden spec.storage.storageClassName"},
+		},
+		{
+			name:     "storage class unset",
+			storage:  &aimsv1.GrafanaStorage{Size: resource.MustParse("10Gi"), AccessModes: rwo},
+			old:      old,
+			expected: []string{"FieldValueForbidden spec.storage.storageClassName"},
+		},
+		{
+			name:     "existing claim",
+			storage:  &aimsv1.GrafanaStorage{ExistingClaim: "data", AccessModes: rwx},
+			old:      old,
+			expected: []string{},
+		},
+		{
+			name:     "leaving an existing claim",
+			storage:  &aimsv1.GrafanaStorage{Size: resource.MustParse("1Gi"), StorageClassName: &fast},
+			old:      &aimsv1.GrafanaStorage{ExistingClaim: "data"},
+			expected: []string{},
+		},
+		{name: "storage added", storage: old, expected: []string{}},
+		{name: "storage removed", old: old, expected: []string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := fields(validateStorageUpdate(test.storage, test.old, field.NewPath("spec", "storage")))
+			if !reflect.DeepEqual(errs, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, errs)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	two := int32(2)
+	tests := []struct {
+		name     string
+		spec     aimsv1.GrafanaSpec
+		expected []string
+	}{
+		{name: "valid", spec: aimsv1.GrafanaSpec{Image: "grafana/grafana:6.7.3"}, expected: []string{}},
+		{
+			name:     "replicas without database",
+			spec:     aimsv1.GrafanaSpec{Image: "grafana/grafana:6.7.3", Replicas: &two},
+			expected: []string{"FieldValueInvalid spec.replicas"},
+		},
+		{
+			name:     "replicas with database",
+			spec:     aimsv1.GrafanaSpec{Image: "grafana/grafana:6.7.3", Replicas: &two, Database: &aimsv1.GrafanaDatabase{}},
+			expected: []string{},
+		},
+		{
+			name:     "relative prometheus url",
+			spec:     aimsv1.GrafanaSpec{Image: "grafana/grafana:6.7.3", PrometheusURL: "prometheus:9090"},
+			expected: []string{"FieldValueInvalid spec.prometheus_url"},
+		},
+		{
+			name:     "blank password",
+			spec:     aimsv1.GrafanaSpec{Image: "grafana/grafana:6.7.3", Password: "  "},
+			expected: []string{"FieldValueInvalid spec.password"},
+		},
+		{
+			name: "one default datasource",
+			spec: aimsv1.GrafanaSpec{
+				Image:       "grafana/grafana:6.7.3",
+				Datasources: []aimsv1.GrafanaDatasource{{Name: "a", IsDefault: true}, {Name: "b"}},
+			},
+			expected: []string{},
+		},
+		{
+			name: "several default datasources",
+			spec: aimsv1.GrafanaSpec{
+				Image:       "grafana/grafana:6.7.3",
+				Datasources: []aimsv1.GrafanaDatasource{{Name: "a", IsDefault: true}, {Name: "b"}, {Name: "c", IsDefault: true}, {Name: "d", IsDefault: true}},
+			},
+			expected: []string{"FieldValueInvalid spec.datasources[2].isDefault", "FieldValueInvalid spec.datasources[3].isDefault"},
+		},
+		{
+			name: "unnamed secret references",
+			spec: aimsv1.GrafanaSpec{
+				Image:                "grafana/grafana:6.7.3",
+				CredentialsSecretRef: &aimsv1.CredentialsSecretRef{},
+				Database:             &aimsv1.GrafanaDatabase{PasswordSecretRef: &v1.SecretKeySelector{}},
+			},
+			expected: []string{
+				"FieldValueRequired spec.credentialsSecretRef.name",
+				"FieldValueRequired spec.database.passwordSecretRef.key",
+				"FieldValueRequired spec.database.passwordSecretRef.name",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := fields(Validate(&aimsv1.Grafana{Spec: test.spec}))
+			if !reflect.DeepEqual(errs, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,391 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog"
+
+	"github.com/dichque/grafana-operator/pkg/crd"
+)
+
+// Keys of the secret holding the serving certificate. The previous CA stays in the caBundle
+// until it expires so that replicas still serving a certificate it signed remain trusted.
+const (
+	caCertKey         string = "ca.crt"
+	caKeyKey          string = "ca.key"
+	previousCACertKey string = "ca-previous.crt"
+)
+
+const (
+	caValidity   = 10 * 365 * 24 * time.Hour
+	certValidity = 365 * 24 * time.Hour
+
+	// certificates are renewed once they are this close to expiry
+	caRenewBefore   = 365 * 24 * time.Hour
+	certRenewBefore = 30 * 24 * time.Hour
+
+	certCheckPeriod = time.Hour
+)
+
+// CertManager generates the serving certificate of the webhook, stores it in a secret shared by
+// the operator replicas, rotates it before it expires and injects its CA into the webhook
+// configurations
+type CertManager struct {
+	client     kubernetes.Interface
+	namespace  string
+	secretName string
+	service    string
+
+	// ValidatingWebhook and MutatingWebhook name the webhook configurations whose caBundle is
+	// maintained
+	ValidatingWebhook string
+	MutatingWebhook   string
+
+	// ConversionCRDs name the CustomResourceDefinitions converted by the webhook on
+	// ConvertPath, their conversion settings are maintained along with the caBundle
+	ConversionCRDs []string
+	dynamicClient  dynamic.Interface
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+// NewCertManager returns a CertManager for the webhook served behind service in namespace
+func NewCertManager(client kubernetes.Interface, dynamicClient dynamic.Interface, namespace, secretName, service string) *CertManager {
+	return &CertManager{
+		client:        client,
+		dynamicClient: dynamicClient,
+		namespace:     namespace,
+		secretName:    secretName,
+		service:       service,
+	}
+}
+
+// Run keeps the certificate current until stopCh is closed
+func (m *CertManager) Run(stopCh <-chan struct{}) {
+	wait.Until(func() {
+		if err := m.Ensure(); err != nil {
+			klog.Errorf("error ensuring the webhook serving certificate: %s", err)
+		}
+	}, certCheckPeriod, stopCh)
+}
+
+// Ensure creates or renews the certificate as needed, loads it for serving and injects the CA
+// bundle into the webhook configurations
+func (m *CertManager) Ensure() error {
+	secret, err := m.client.CoreV1().Secrets(m.namespace).Get(m.secretName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		secret = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: m.secretName, Namespace: m.namespace},
+			Type:       v1.SecretTypeTLS,
+		}
+		if err = m.renew(secret); err != nil {
+			return err
+		}
+		secret, err = m.client.CoreV1().Secrets(m.namespace).Create(secret)
+	} else if err == nil && m.needsRenewal(secret) {
+		secret = secret.DeepCopy()
+		if err = m.renew(secret); err != nil {
+			return err
+		}
+		klog.Infof("rotating webhook serving certificate %s/%s", m.namespace, m.secretName)
+		secret, err = m.client.CoreV1().Secrets(m.namespace).Update(secret)
+	}
+	if errors.IsAlreadyExists(err) || errors.IsConflict(err) {
+		// Another replica issued a certificate first, use that one
+		return m.Ensure()
+	}
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.X509KeyPair(secret.Data[v1.TLSCertKey], secret.Data[v1.TLSPrivateKeyKey])
+	if err != nil {
+		return fmt.Errorf("invalid certificate in secret %s/%s: %s", m.namespace, m.secretName, err)
+	}
+	m.mu.Lock()
+	m.cert = &cert
+	m.mu.Unlock()
+
+	return m.injectCABundle(caBundle(secret))
+}
+
+// GetCertificate serves the current certificate, see tls.Config
+func (m *CertManager) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.cert == nil {
+		return nil, fmt.Errorf("webhook serving certificate not loaded yet")
+	}
+	return m.cert, nil
+}
+
+// dnsNames are the names the API server uses to reach the webhook service
+func (m *CertManager) dnsNames() []string {
+	return []string{
+		m.service,
+		m.service + "." + m.namespace,
+		m.service + "." + m.namespace + ".svc",
+		m.service + "." + m.namespace + ".svc.cluster.local",
+	}
+}
+
+// needsRenewal reports whether the certificate in secret is missing, about to expire or issued
+// for another service
+func (m *CertManager) needsRenewal(secret *v1.Secret) bool {
+	cert, err := parseCert(secret.Data[v1.TLSCertKey])
+	if err != nil {
+		return true
+	}
+	if time.Now().Add(certRenewBefore).After(cert.NotAfter) {
+		return true
+	}
+	if cert.VerifyHostname(m.service+"."+m.namespace+".svc") != nil {
+		return true
+	}
+	ca, err := parseCert(secret.Data[caCertKey])
+	return err != nil || time.Now().Add(caRenewBefore).After(ca.NotAfter)
+}
+
+// renew issues a new serving certificate into secret, along with a new CA when the current one
+// is missing or about to expire
+func (m *CertManager) renew(secret *v1.Secret) error {
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+
+	ca, caKey, err := parseKeyPair(secret.Data[caCertKey], secret.Data[caKeyKey])
+	if err != nil || time.Now().Add(caRenewBefore).After(ca.NotAfter) {
+		if err == nil {
+			secret.Data[previousCACertKey] = secret.Data[caCertKey]
+		}
+		ca, caKey, err = newCA()
+		if err != nil {
+			return err
+		}
+		secret.Data[caCertKey] = encodeCert(ca)
+		secret.Data[caKeyKey] = encodeKey(caKey)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return err
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: m.service + "." + m.namespace + ".svc"},
+		DNSNames:     m.dnsNames(),
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(certValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return err
+	}
+	secret.Data[v1.TLSCertKey] = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	secret.Data[v1.TLSPrivateKeyKey] = encodeKey(key)
+	return nil
+}
+
+// caBundle returns the CAs the API server must trust: the current one and the previous one
+// until it expires
+func caBundle(secret *v1.Secret) []byte {
+	bundle := append([]byte{}, secret.Data[caCertKey]...)
+	if previous, err := parseCert(secret.Data[previousCACertKey]); err == nil && time.Now().Before(previous.NotAfter) {
+		bundle = append(bundle, secret.Data[previousCACertKey]...)
+	}
+	return bundle
+}
+
+// injectCABundle sets caBundle on every webhook of the configurations the manager maintains
+func (m *CertManager) injectCABundle(bundle []byte) error {
+	if m.ValidatingWebhook != "" {
+		client := m.client.AdmissionregistrationV1().ValidatingWebhookConfigurations()
+		config, err := client.Get(m.ValidatingWebhook, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		config = config.DeepCopy()
+		changed := false
+		for i := range config.Webhooks {
+			changed = setCABundle(&config.Webhooks[i].ClientConfig, bundle) || changed
+		}
+		if changed {
+			klog.Infof("updating caBundle of validating webhook configuration %s", m.ValidatingWebhook)
+			if _, err = client.Update(config); err != nil {
+				return err
+			}
+		}
+	}
+
+	if m.MutatingWebhook != "" {
+		client := m.client.AdmissionregistrationV1().MutatingWebhookConfigurations()
+		config, err := client.Get(m.MutatingWebhook, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		config = config.DeepCopy()
+		changed := false
+		for i := range config.Webhooks {
+			changed = setCABundle(&config.Webhooks[i].ClientConfig, bundle) || changed
+		}
+		if changed {
+			klog.Infof("updating caBundle of mutating webhook configuration %s", m.MutatingWebhook)
+			if _, err = client.Update(config); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, name := range m.ConversionCRDs {
+		if err := m.injectConversion(name, bundle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// injectConversion points the conversion of a CustomResourceDefinition to the webhook and
+// serves all of its versions
+func (m *CertManager) injectConversion(name string, bundle []byte) error {
+	client := m.dynamicClient.Resource(crd.GVR)
+	found, err := client.Get(name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	port := int64(443)
+	conversion := map[string]interface{}{
+		"strategy": "Webhook",
+		"webhook": map[string]interface{}{
+			"conversionReviewVersions": []interface{}{"v1", "v1beta1"},
+			"clientConfig": map[string]interface{}{
+				"service": map[string]interface{}{
+					"namespace": m.namespace,
+					"name":      m.service,
+					"path":      ConvertPath,
+					"port":      port,
+				},
+				"caBundle": base64.StdEncoding.EncodeToString(bundle),
+			},
+		},
+	}
+	updated := found.DeepCopy()
+	if err = unstructured.SetNestedField(updated.Object, conversion, "spec", "conversion"); err != nil {
+		return err
+	}
+	// Versions other than the storage one are only served once they can be converted
+	versions, _, _ := unstructured.NestedSlice(updated.Object, "spec", "versions")
+	for _, version := range versions {
+		if version, ok := version.(map[string]interface{}); ok {
+			version["served"] = true
+		}
+	}
+	if err = unstructured.SetNestedSlice(updated.Object, versions, "spec", "versions"); err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(found.Object["spec"], updated.Object["spec"]) {
+		return nil
+	}
+
+	klog.Infof("updating conversion webhook of crd %s", name)
+	_, err = client.Update(updated, metav1.UpdateOptions{})
+	return err
+}
+
+// setCABundle sets the caBundle of a webhook and reports whether it changed
+func setCABundle(config *admissionregistrationv1.WebhookClientConfig, bundle []byte) bool {
+	if bytes.Equal(config.CABundle, bundle) {
+		return false
+	}
+	config.CABundle = bundle
+	return true
+}
+
+// newCA returns a self-signed CA certificate and its key
+func newCA() (*x509.Certificate, *rsa.PrivateKey, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: fmt.Sprintf("grafana-operator-webhook-ca@%d", now.Unix())},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	return cert, key, err
+}
+
+func serialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+func encodeCert(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+}
+
+func encodeKey(key *rsa.PrivateKey) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+func parseCert(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM certificate")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func parseKeyPair(certData, keyData []byte) (*x509.Certificate, *rsa.PrivateKey, error) {
+	cert, err := parseCert(certData)
+	if err != nil {
+		return nil, nil, err
+	}
+	block, _ := pem.Decode(keyData)
+	if block == nil {
+		return nil, nil, fmt.Errorf("no PEM key")
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	return cert, key, err
+}
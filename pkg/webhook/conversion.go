@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog"
+
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+	aimsv1beta2 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1beta2"
+)
+
+// ConvertPath is the path the conversion webhook is served on
+const ConvertPath string = "/convert"
+
+// conversionReview mirrors the ConversionReview of apiextensions.k8s.io v1 and v1beta1, which
+// share their encoding. The apiextensions types are not a dependency of the operator.
+type conversionReview struct {
+	metav1.TypeMeta `json:",inline"`
+	Request         *conversionRequest  `json:"request,omitempty"`
+	Response        *conversionResponse `json:"response,omitempty"`
+}
+
+type conversionRequest struct {
+	UID               types.UID              `json:"uid"`
+	DesiredAPIVersion string                 `json:"desiredAPIVersion"`
+	Objects           []runtime.RawExtension `json:"objects"`
+}
+
+type conversionResponse struct {
+	UID              types.UID              `json:"uid"`
+	ConvertedObjects []runtime.RawExtension `json:"convertedObjects"`
+	Result           metav1.Status          `json:"result"`
+}
+
+// converters convert Grafana objects between API versions, by source and desired version
+var converters = map[string]map[string]func(map[string]interface{}) error{
+	aimsv1.SchemeGroupVersion.String(): {
+		aimsv1beta2.SchemeGroupVersion.String(): aimsv1beta2.ConvertFromV1,
+	},
+	aimsv1beta2.SchemeGroupVersion.String(): {
+		aimsv1.SchemeGroupVersion.String(): aimsv1beta2.ConvertToV1,
+	},
+}
+
+// HandleConversion registers the conversion webhook of the Grafana CRD on path
+func (s *Server) HandleConversion(path string) {
+	s.mux.HandleFunc(path, serveConversion)
+}
+
+// serveConversion converts the objects of a conversion review to the desired version
+func serveConversion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "conversion reviews must be posted", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	review := &conversionReview{}
+	if err = json.Unmarshal(body, review); err != nil || review.Request == nil {
+		http.Error(w, "malformed conversion review", http.StatusBadRequest)
+		return
+	}
+
+	response := &conversionResponse{UID: review.Request.UID, Result: metav1.Status{Status: metav1.StatusSuccess}}
+	converted, err := convert(review.Request.Objects, review.Request.DesiredAPIVersion)
+	if err != nil {
+		klog.Errorf("error converting to %s: %s", review.Request.DesiredAPIVersion, err)
+		response.Result = metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+	} else {
+		response.ConvertedObjects = converted
+	}
+	review.Request = nil
+	review.Response = response
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(review); err != nil {
+		klog.Errorf("error writing conversion review: %s", err)
+	}
+}
+
+// convert converts objects to desiredAPIVersion, failing as a whole when one object fails
+func convert(objects []runtime.RawExtension, desiredAPIVersion string) ([]runtime.RawExtension, error) {
+	converted := []runtime.RawExtension{}
+	for _, object := range objects {
+		// Numbers stay as they were encoded rather than passing through float64
+		obj := map[string]interface{}{}
+		decoder := json.NewDecoder(bytes.NewReader(object.Raw))
+		decoder.UseNumber()
+		if err := decoder.Decode(&obj); err != nil {
+			return nil, err
+		}
+		apiVersion, _ := obj["apiVersion"].(string)
+		if apiVersion != desiredAPIVersion {
+			converter, ok := converters[apiVersion][desiredAPIVersion]
+			if !ok {
+				return nil, fmt.Errorf("cannot convert %v from %s to %s", obj["kind"], apiVersion, desiredAPIVersion)
+			}
+			if err := converter(obj); err != nil {
+				return nil, err
+			}
+		}
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		converted = append(converted, runtime.RawExtension{Raw: data})
+	}
+	return converted, nil
+}
@@ -0,0 +1,140 @@
+package webhook
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog"
+
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+	"github.com/dichque/grafana-operator/pkg/defaults"
+)
+
+// DefaultGrafanaPath is the path the Grafana defaulting webhook is served on
+const DefaultGrafanaPath string = "/default-grafana"
+
+// patchOperation is an operation of a JSON patch, RFC 6902
+type patchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// DefaultGrafana returns the handler filling the unset fields of Grafana resources from d, and
+// pinning their image to a digest with pinner. Images are only pinned when they are created or
+// changed, or still carry a tag because an earlier admission did not get the digest in time.
+func DefaultGrafana(d *defaults.Defaults, pinner *defaults.Pinner) Handler {
+	return func(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+		if req.SubResource != "" || (req.Operation != admissionv1.Create && req.Operation != admissionv1.Update) {
+			return allowed()
+		}
+
+		grafana := &aimsv1.Grafana{}
+		if err := json.Unmarshal(req.Object.Raw, grafana); err != nil {
+			return denied(errors.NewBadRequest(err.Error()).ErrStatus)
+		}
+		if grafana.DeletionTimestamp != nil {
+			return allowed()
+		}
+		original := grafana.DeepCopy()
+
+		applied := d.Apply(grafana)
+		if imageChanged(req, grafana) {
+			pinned, err := pinner.Pin(grafana.Spec.Image)
+			if err != nil {
+				// Admission goes on with the tag rather than blocking on the registry
+				klog.Warningf("not pinning the image of grafana %s/%s: %s", req.Namespace, req.Name, err)
+			} else if pinned != grafana.Spec.Image {
+				grafana.Spec.Image = pinned
+				applied = append(applied, defaults.ImageDigest)
+			}
+		}
+		if len(applied) == 0 {
+			return allowed()
+		}
+		defaults.Record(grafana, applied)
+
+		patch, err := grafanaPatch(req.Object.Raw, original, grafana)
+		if err != nil {
+			return denied(errors.NewInternalError(err).ErrStatus)
+		}
+		patchType := admissionv1.PatchTypeJSONPatch
+		return &admissionv1.AdmissionResponse{Allowed: true, Patch: patch, PatchType: &patchType}
+	}
+}
+
+// imageChanged reports whether the request sets the image of grafana, it was defaulted or it is
+// not pinned yet
+func imageChanged(req *admissionv1.AdmissionRequest, grafana *aimsv1.Grafana) bool {
+	if req.Operation != admissionv1.Update || !strings.Contains(grafana.Spec.Image, "@") {
+		return true
+	}
+	old := &aimsv1.Grafana{}
+	if err := json.Unmarshal(req.OldObject.Raw, old); err != nil {
+		return false
+	}
+	return old.Spec.Image != grafana.Spec.Image
+}
+
+// grafanaPatch returns the JSON patch turning original into grafana, limited to the top level
+// spec fields and the defaults annotation so that fields the operator does not know are kept
+func grafanaPatch(raw []byte, original, grafana *aimsv1.Grafana) ([]byte, error) {
+	object := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, err
+	}
+	before, err := toMap(original.Spec)
+	if err != nil {
+		return nil, err
+	}
+	after, err := toMap(grafana.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := map[string]interface{}{}
+	keys := []string{}
+	for key, value := range after {
+		if !equality.Semantic.DeepEqual(before[key], value) {
+			changed[key] = value
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	patch := []patchOperation{}
+	if _, ok := object["spec"]; !ok {
+		patch = append(patch, patchOperation{Op: "add", Path: "/spec", Value: changed})
+	} else {
+		for _, key := range keys {
+			patch = append(patch, patchOperation{Op: "add", Path: "/spec/" + escapePointer(key), Value: changed[key]})
+		}
+	}
+
+	value := grafana.Annotations[defaults.AppliedAnnotation]
+	if metadata, _ := object["metadata"].(map[string]interface{}); metadata["annotations"] == nil {
+		patch = append(patch, patchOperation{Op: "add", Path: "/metadata/annotations", Value: map[string]string{defaults.AppliedAnnotation: value}})
+	} else {
+		patch = append(patch, patchOperation{Op: "add", Path: "/metadata/annotations/" + escapePointer(defaults.AppliedAnnotation), Value: value})
+	}
+	return json.Marshal(patch)
+}
+
+// toMap returns the JSON object v encodes to
+func toMap(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]interface{}{}
+	return m, json.Unmarshal(data, &m)
+}
+
+// escapePointer escapes a key for a JSON pointer, RFC 6901
+func escapePointer(key string) string {
+	return strings.Replace(strings.Replace(key, "~", "~0", -1), "/", "~1", -1)
+}
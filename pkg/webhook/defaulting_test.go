@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+	"github.com/dichque/grafana-operator/pkg/defaults"
+)
+
+func TestDefaultGrafanaPinning(t *testing.T) {
+	one := int32(1)
+	d := &defaults.Defaults{Image: "grafana/grafana:6.7.3", Digests: map[string]string{
+		"grafana/grafana:6.7.3": "sha256:aaaa",
+		"grafana/grafana:7.0.0": "sha256:bbbb",
+	}}
+	pinner := defaults.NewPinner(d)
+
+	tests := []struct {
+		name      string
+		old       string
+		image     string
+		operation admissionv1.Operation
+		expected  string
+	}{
+		{name: "created", image: "grafana/grafana:6.7.3", operation: admissionv1.Create, expected: "grafana/grafana:6.7.3@sha256:aaaa"},
+		{name: "defaulted", operation: admissionv1.Create, expected: "grafana/grafana:6.7.3@sha256:aaaa"},
+		{
+			name:      "changed",
+			old:       "grafana/grafana:6.7.3@sha256:aaaa",
+			image:     "grafana/grafana:7.0.0",
+			operation: admissionv1.Update,
+			expected:  "grafana/grafana:7.0.0@sha256:bbbb",
+		},
+		{name: "unchanged tag", old: "grafana/grafana:6.7.3", image: "grafana/grafana:6.7.3", operation: admissionv1.Update, expected: "grafana/grafana:6.7.3@sha256:aaaa"},
+		{name: "unchanged digest", old: "grafana/grafana:6.7.3@sha256:cccc", image: "grafana/grafana:6.7.3@sha256:cccc", operation: admissionv1.Update},
+		{name: "unknown tag", old: "grafana/grafana:8.0.0", image: "grafana/grafana:8.0.0", operation: admissionv1.Update},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec := aimsv1.GrafanaSpec{Image: test.image, Replicas: &one, Username: "admin", PrometheusURL: "http://prometheus:9090"}
+			raw, err := json.Marshal(&aimsv1.Grafana{Spec: spec})
+			if err != nil {
+				t.Fatal(err)
+			}
+			spec.Image = test.old
+			oldRaw, err := json.Marshal(&aimsv1.Grafana{Spec: spec})
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := &admissionv1.AdmissionRequest{
+				Operation: test.operation,
+				Object:    runtime.RawExtension{Raw: raw},
+				OldObject: runtime.RawExtension{Raw: oldRaw},
+			}
+
+			resp := DefaultGrafana(d, pinner)(req)
+			if !resp.Allowed {
+				t.Fatalf("expected the request to be allowed: %v", resp.Result)
+			}
+			patch := []patchOperation{}
+			if resp.Patch != nil {
+				if err = json.Unmarshal(resp.Patch, &patch); err != nil {
+					t.Fatal(err)
+				}
+			}
+			image := ""
+			for _, op := range patch {
+				if op.Path == "/spec/image" {
+					image, _ = op.Value.(string)
+				}
+			}
+			if image != test.expected {
+				t.Errorf("expected image %q, got %q", test.expected, image)
+			}
+			if test.expected != "" && !strings.Contains(string(resp.Patch), defaults.ImageDigest) {
+				t.Errorf("expected %s to be recorded in %s", defaults.ImageDigest, resp.Patch)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package webhook
+
+import (
+	"encoding/json"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+	clientset "github.com/dichque/grafana-operator/pkg/client/clientset/versioned"
+	"github.com/dichque/grafana-operator/pkg/validation"
+)
+
+// ValidateGrafanaPath is the path the Grafana validating webhook is served on
+const ValidateGrafanaPath string = "/validate-grafana"
+
+// ValidateGrafana returns the handler rejecting Grafana resources the controller would refuse to
+// roll out, and changes the created objects cannot follow. Scale requests are checked against
+// the stored resource, read with client.
+func ValidateGrafana(client clientset.Interface) Handler {
+	return func(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+		switch req.SubResource {
+		case "":
+			return validateGrafana(req)
+		case "scale":
+			return validateScale(client, req)
+		}
+		return allowed()
+	}
+}
+
+func validateGrafana(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+
+	grafana := &aimsv1.Grafana{}
+	if err := json.Unmarshal(req.Object.Raw, grafana); err != nil {
+		return denied(errors.NewBadRequest(err.Error()).ErrStatus)
+	}
+
+	var errs field.ErrorList
+	switch req.Operation {
+	case admissionv1.Create:
+		errs = validation.Validate(grafana)
+	case admissionv1.Update:
+		old := &aimsv1.Grafana{}
+		if err := json.Unmarshal(req.OldObject.Raw, old); err != nil {
+			return denied(errors.NewBadRequest(err.Error()).ErrStatus)
+		}
+		// Resources created before the webhook may hold invalid specs, they can still be
+		// relabeled and have their finalizers removed
+		if grafana.DeletionTimestamp != nil || apiequality.Semantic.DeepEqual(grafana.Spec, old.Spec) {
+			return allowed()
+		}
+		errs = validation.ValidateUpdate(grafana, old)
+	default:
+		return allowed()
+	}
+
+	if len(errs) == 0 {
+		return allowed()
+	}
+	return denied(errors.NewInvalid(aimsv1.SchemeGroupVersion.WithKind("Grafana").GroupKind(), grafana.Name, errs).ErrStatus)
+}
+
+// validateScale rejects scaling a Grafana resource to replicas its stored spec cannot run
+func validateScale(client clientset.Interface, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if req.Operation != admissionv1.Update {
+		return allowed()
+	}
+	scale := &autoscalingv1.Scale{}
+	if err := json.Unmarshal(req.Object.Raw, scale); err != nil {
+		return denied(errors.NewBadRequest(err.Error()).ErrStatus)
+	}
+
+	grafana, err := client.AimsV1().Grafanas(req.Namespace).Get(req.Name, metav1.GetOptions{})
+	if err != nil {
+		if status, ok := err.(errors.APIStatus); ok {
+			return denied(status.Status())
+		}
+		return denied(errors.NewInternalError(err).ErrStatus)
+	}
+	if grafana.Spec.Replicas != nil && *grafana.Spec.Replicas == scale.Spec.Replicas {
+		return allowed()
+	}
+	replicas := scale.Spec.Replicas
+	grafana.Spec.Replicas = &replicas
+
+	if errs := validation.ValidateReplicas(grafana); len(errs) > 0 {
+		return denied(errors.NewInvalid(aimsv1.SchemeGroupVersion.WithKind("Grafana").GroupKind(), grafana.Name, errs).ErrStatus)
+	}
+	return allowed()
+}
@@ -0,0 +1,71 @@
+package webhook
+
+import (
+	"encoding/json"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+	"github.com/dichque/grafana-operator/pkg/client/clientset/versioned/fake"
+)
+
+func TestValidateScale(t *testing.T) {
+	one, two := int32(1), int32(2)
+	tests := []struct {
+		name      string
+		spec      aimsv1.GrafanaSpec
+		replicas  int32
+		operation admissionv1.Operation
+		allowed   bool
+	}{
+		{name: "scale down", spec: aimsv1.GrafanaSpec{Replicas: &one}, replicas: 0, operation: admissionv1.Update, allowed: true},
+		{name: "scale up without database", spec: aimsv1.GrafanaSpec{Replicas: &one}, replicas: 3, operation: admissionv1.Update},
+		{
+			name:      "scale up with database",
+			spec:      aimsv1.GrafanaSpec{Replicas: &one, Database: &aimsv1.GrafanaDatabase{}},
+			replicas:  3,
+			operation: admissionv1.Update,
+			allowed:   true,
+		},
+		{name: "unchanged replicas", spec: aimsv1.GrafanaSpec{Replicas: &two}, replicas: 2, operation: admissionv1.Update, allowed: true},
+		{name: "unset replicas", replicas: 2, operation: admissionv1.Update},
+		{name: "other operation", replicas: 3, operation: admissionv1.Create, allowed: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			grafana := &aimsv1.Grafana{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns"}, Spec: test.spec}
+			client := fake.NewSimpleClientset(grafana)
+
+			scale := &autoscalingv1.Scale{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns"}, Spec: autoscalingv1.ScaleSpec{Replicas: test.replicas}}
+			raw, err := json.Marshal(scale)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := &admissionv1.AdmissionRequest{
+				Name:        "a",
+				Namespace:   "ns",
+				SubResource: "scale",
+				Operation:   test.operation,
+				Object:      runtime.RawExtension{Raw: raw},
+			}
+
+			resp := ValidateGrafana(client)(req)
+			if resp.Allowed != test.allowed {
+				t.Errorf("expected allowed %v, got %v: %v", test.allowed, resp.Allowed, resp.Result)
+			}
+		})
+	}
+}
+
+func TestValidateScaleMissingGrafana(t *testing.T) {
+	raw, _ := json.Marshal(&autoscalingv1.Scale{Spec: autoscalingv1.ScaleSpec{Replicas: 2}})
+	req := &admissionv1.AdmissionRequest{Name: "a", Namespace: "ns", SubResource: "scale", Operation: admissionv1.Update, Object: runtime.RawExtension{Raw: raw}}
+	if resp := ValidateGrafana(fake.NewSimpleClientset())(req); resp.Allowed {
+		t.Error("expected scaling a missing grafana to be denied")
+	}
+}
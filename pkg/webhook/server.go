@@ -0,0 +1,114 @@
+// Package webhook serves the admission webhooks of the operator over HTTPS, with a serving
+// certificate the operator manages itself
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+)
+
+// maxRequestBytes bounds the size of admission reviews, the API server limits objects to 3MiB
+const maxRequestBytes int64 = 4 << 20
+
+// Handler answers an admission request
+type Handler func(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse
+
+// Server serves admission handlers with the certificate of a CertManager
+type Server struct {
+	port  int
+	certs *CertManager
+	mux   *http.ServeMux
+}
+
+// NewServer returns a Server listening on port
+func NewServer(port int, certs *CertManager) *Server {
+	return &Server{port: port, certs: certs, mux: http.NewServeMux()}
+}
+
+// Handle registers the handler of the admission reviews posted to path
+func (s *Server) Handle(path string, handler Handler) {
+	s.mux.HandleFunc(path, serve(handler))
+}
+
+// Run serves until stopCh is closed
+func (s *Server) Run(stopCh <-chan struct{}) error {
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%d", s.port),
+		Handler: s.mux,
+		TLSConfig: &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: s.certs.GetCertificate,
+		},
+	}
+
+	go func() {
+		<-stopCh
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			klog.Errorf("error shutting down the webhook server: %s", err)
+		}
+	}()
+
+	klog.Infof("serving admission webhooks on port %d", s.port)
+	if err := server.ListenAndServeTLS("", ""); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// serve decodes the admission review of a request and encodes the answer of handler. Reviews
+// of admission.k8s.io v1 and v1beta1 share their encoding, the answer uses the version of the
+// request.
+func serve(handler Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "admission reviews must be posted", http.StatusMethodNotAllowed)
+			return
+		}
+		if contentType := r.Header.Get("Content-Type"); contentType != "application/json" {
+			http.Error(w, fmt.Sprintf("unsupported content type %q", contentType), http.StatusUnsupportedMediaType)
+			return
+		}
+
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		review := &admissionv1.AdmissionReview{}
+		if err = json.Unmarshal(body, review); err != nil || review.Request == nil {
+			http.Error(w, "malformed admission review", http.StatusBadRequest)
+			return
+		}
+
+		response := handler(review.Request)
+		response.UID = review.Request.UID
+		review.Response = response
+		review.Request = nil
+
+		w.Header().Set("Content-Type", "application/json")
+		if err = json.NewEncoder(w).Encode(review); err != nil {
+			klog.Errorf("error writing admission review: %s", err)
+		}
+	}
+}
+
+// allowed admits a request
+func allowed() *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{Allowed: true}
+}
+
+// denied rejects a request with status
+func denied(status metav1.Status) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{Allowed: false, Result: &status}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+	"github.com/dichque/grafana-operator/pkg/util"
+)
+
+// pruneAnnotation turns pruning off ("false") or into a report ("dry-run") when set on a
+// Grafana resource. Set to "false" on a child object it keeps that object.
+const pruneAnnotation string = "aims.cisco.com/prune"
+
+const (
+	pruneDisabled string = "false"
+	pruneDryRun   string = "dry-run"
+)
+
+// inventory holds the objects an instance wants, keyed by kind and name
+type inventory map[string]bool
+
+func (i inventory) add(kind, name string) {
+	i[kind+"/"+name] = true
+}
+
+func (i inventory) has(kind, name string) bool {
+	return i[kind+"/"+name]
+}
+
+// prunable is a kind of child object: how to list those labeled for an instance and how to
+// delete one of them
+type prunable struct {
+	kind   string
+	list   func(grafana *aimsv1.Grafana) ([]metav1.Object, error)
+	delete func(namespace, name string) error
+}
+
+// desiredInventory lists the objects the last render of the instance wants
+func desiredInventory(grafana *aimsv1.Grafana, cms []v1.ConfigMap, folders util.DashboardFolders, datasourceSecret *v1.Secret, deploy *appsv1.Deployment, routes bool) inventory {
+	desired := inventory{}
+	for _, cm := range cms {
+		desired.add("ConfigMap", cm.Name)
+	}
+	// Written by the dashboard controller, one per folder
+	desired.add("ConfigMap", util.CustomDashboardsName(grafana))
+	for _, folder := range folders {
+		desired.add("ConfigMap", util.CustomDashboardsFolderName(grafana, folder))
+	}
+
+	desired.add("Secret", datasourceSecret.Name)
+	// Generated credentials and secret_key cannot be recreated once deleted, they go
+	// with the instance only
+	desired.add("Secret", util.AdminSecretName(grafana))
+	desired.add("Secret", util.SecretKeySecretName(grafana))
+
+	desired.add("Deployment", deploy.Name)
+	desired.add("Service", util.ServiceName(grafana))
+	if grafana.Spec.Ingress != nil {
+		if routes {
+			desired.add("Route", util.IngressName(grafana))
+		} else {
+			desired.add("Ingress", util.IngressName(grafana))
+		}
+	}
+	return desired
+}
+
+// prune deletes the objects labeled for the instance that are controlled by it but missing
+// from the desired inventory. Each deletion, or planned deletion in dry-run mode, is recorded
+// in an event on the instance.
+func (c *Controller) prune(grafana *aimsv1.Grafana, desired inventory) error {
+	mode := grafana.Annotations[pruneAnnotation]
+	if mode == pruneDisabled {
+		return nil
+	}
+
+	for _, kind := range c.prunables() {
+		objects, err := kind.list(grafana)
+		if err != nil {
+			return err
+		}
+		sort.Slice(objects, func(i, j int) bool {
+			return objects[i].GetName() < objects[j].GetName()
+		})
+
+		for _, obj := range objects {
+			if desired.has(kind.kind, obj.GetName()) || !metav1.IsControlledBy(obj, grafana) {
+				continue
+			}
+			if obj.GetAnnotations()[pruneAnnotation] == pruneDisabled || obj.GetDeletionTimestamp() != nil {
+				continue
+			}
+
+			if mode == pruneDryRun {
+				c.recorder.Eventf(grafana, v1.EventTypeNormal, "PruneDryRun", "would delete %s %s, no longer desired", kind.kind, obj.GetName())
+				continue
+			}
+			if err = kind.delete(obj.GetNamespace(), obj.GetName()); err != nil && !errors.IsNotFound(err) {
+				return fmt.Errorf("unable to prune %s %s: %s", kind.kind, obj.GetName(), err)
+			}
+			klog.Infof("%s pruned: %s", kind.kind, obj.GetName())
+			c.recorder.Eventf(grafana, v1.EventTypeNormal, "Pruned", "deleted %s %s, no longer desired", kind.kind, obj.GetName())
+		}
+	}
+	return nil
+}
+
+// prunables returns the kinds of child objects the operator creates. The data claim is not
+// one of them: it holds the grafana database and only goes with the instance, according to
+// its retain policy.
+func (c *Controller) prunables() []prunable {
+	core := c.kubeClientset.CoreV1()
+	kinds := []prunable{
+		{
+			kind: "ConfigMap",
+			list: func(grafana *aimsv1.Grafana) ([]metav1.Object, error) {
+				items, err := c.configMapLister.ConfigMaps(grafana.Namespace).List(util.Selector(grafana))
+				objects := []metav1.Object{}
+				for _, item := range items {
+					objects = append(objects, item)
+				}
+				return objects, err
+			},
+			delete: func(namespace, name string) error {
+				return core.ConfigMaps(namespace).Delete(name, &metav1.DeleteOptions{})
+			},
+		},
+		{
+			kind: "Secret",
+			list: func(grafana *aimsv1.Grafana) ([]metav1.Object, error) {
+				items, err := c.secretLister.Secrets(grafana.Namespace).List(util.Selector(grafana))
+				objects := []metav1.Object{}
+				for _, item := range items {
+					objects = append(objects, item)
+				}
+				return objects, err
+			},
+			delete: func(namespace, name string) error {
+				return core.Secrets(namespace).Delete(name, &metav1.DeleteOptions{})
+			},
+		},
+		{
+			kind: "Deployment",
+			list: func(grafana *aimsv1.Grafana) ([]metav1.Object, error) {
+				items, err := c.deploymentLister.Deployments(grafana.Namespace).List(util.Selector(grafana))
+				objects := []metav1.Object{}
+				for _, item := range items {
+					objects = append(objects, item)
+				}
+				return objects, err
+			},
+			delete: func(namespace, name string) error {
+				return c.kubeClientset.AppsV1().Deployments(namespace).Delete(name, &metav1.DeleteOptions{})
+			},
+		},
+		{
+			kind: "Service",
+			list: func(grafana *aimsv1.Grafana) ([]metav1.Object, error) {
+				items, err := c.serviceLister.Services(grafana.Namespace).List(util.Selector(grafana))
+				objects := []metav1.Object{}
+				for _, item := range items {
+					objects = append(objects, item)
+				}
+				return objects, err
+			},
+			delete: func(namespace, name string) error {
+				return core.Services(namespace).Delete(name, &metav1.DeleteOptions{})
+			},
+		},
+	}
+
+	if c.routesAvailable {
+		kinds = append(kinds, prunable{
+			kind: "Route",
+			list: func(grafana *aimsv1.Grafana) ([]metav1.Object, error) {
+				items, err := c.routeLister.ByNamespace(grafana.Namespace).List(util.Selector(grafana))
+				if err != nil {
+					return nil, err
+				}
+				objects := []metav1.Object{}
+				for _, item := range items {
+					object, err := meta.Accessor(item)
+					if err != nil {
+						return nil, err
+					}
+					objects = append(objects, object)
+				}
+				return objects, nil
+			},
+			delete: func(namespace, name string) error {
+				return c.dynamicClient.Resource(util.RouteGVR).Namespace(namespace).Delete(name, &metav1.DeleteOptions{})
+			},
+		})
+	} else {
+		kinds = append(kinds, prunable{
+			kind: "Ingress",
+			list: func(grafana *aimsv1.Grafana) ([]metav1.Object, error) {
+				items, err := c.ingressLister.Ingresses(grafana.Namespace).List(util.Selector(grafana))
+				objects := []metav1.Object{}
+				for _, item := range items {
+					objects = append(objects, item)
+				}
+				return objects, err
+			},
+			delete: func(namespace, name string) error {
+				return c.kubeClientset.NetworkingV1beta1().Ingresses(namespace).Delete(name, &metav1.DeleteOptions{})
+			},
+		})
+	}
+	return kinds
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+	"github.com/dichque/grafana-operator/pkg/util"
+)
+
+func TestDesiredInventory(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    aimsv1.GrafanaSpec
+		folders util.DashboardFolders
+		routes  bool
+		desired []string
+		pruned  []string
+	}{
+		{
+			name: "defaults",
+			desired: []string{
+				"ConfigMap/a-config", "ConfigMap/a-grafana-custom-dashboards", "Secret/a-datasources",
+				"Secret/a-grafana-admin", "Deployment/a-grafana", "Service/a-grafana",
+			},
+			pruned: []string{"Ingress/a-grafana", "Route/a-grafana", "ConfigMap/a-dashboards-old"},
+		},
+		{
+			name:    "ingress",
+			spec:    aimsv1.GrafanaSpec{Ingress: &aimsv1.GrafanaIngress{Host: "grafana.example.com"}},
+			desired: []string{"Ingress/a-grafana"},
+			pruned:  []string{"Route/a-grafana"},
+		},
+		{
+			name:    "route",
+			spec:    aimsv1.GrafanaSpec{Ingress: &aimsv1.GrafanaIngress{Host: "grafana.example.com"}},
+			routes:  true,
+			desired: []string{"Route/a-grafana"},
+			pruned:  []string{"Ingress/a-grafana"},
+		},
+		{
+			name:    "dashboard folders",
+			folders: util.DashboardFolders{"a.json": {}, "b.json": {Title: "Kafka"}},
+			desired: []string{"ConfigMap/a-grafana-custom-dashboards", "ConfigMap/" + util.CustomDashboardsFolderName(&aimsv1.Grafana{ObjectMeta: metav1.ObjectMeta{Name: "a"}}, util.DashboardFolder{Title: "Kafka"})},
+		},
+		{
+			name:    "database",
+			spec:    aimsv1.GrafanaSpec{Database: &aimsv1.GrafanaDatabase{}, Password: "inline"},
+			desired: []string{"Secret/a-grafana-admin", "Secret/a-grafana-secret-key"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			grafana := &aimsv1.Grafana{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns"}, Spec: test.spec}
+			cms := []v1.ConfigMap{{ObjectMeta: metav1.ObjectMeta{Name: "a-config"}}}
+			secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "a-datasources"}}
+			deploy := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "a-grafana"}}
+
+			inventory := desiredInventory(grafana, cms, test.folders, secret, deploy, test.routes)
+			for _, key := range test.desired {
+				if !inventory[key] {
+					t.Errorf("expected %s in %v", key, inventory)
+				}
+			}
+			for _, key := range test.pruned {
+				if inventory[key] {
+					t.Errorf("unexpected %s in %v", key, inventory)
+				}
+			}
+		})
+	}
+}
+
+// TestPrunablesKeepClaims makes sure the data claim is never pruned, whatever the spec says
+func TestPrunablesKeepClaims(t *testing.T) {
+	for _, routes := range []bool{false, true} {
+		c := &Controller{kubeClientset: fake.NewSimpleClientset(), routesAvailable: routes}
+		for _, kind := range c.prunables() {
+			if kind.kind == "PersistentVolumeClaim" {
+				t.Errorf("persistent volume claims are prunable with routes=%v", routes)
+			}
+		}
+	}
+}
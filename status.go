@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+	"github.com/dichque/grafana-operator/pkg/conditions"
+	"github.com/dichque/grafana-operator/pkg/util"
+)
+
+// setConfigMapCondition records the outcome of the configmap reconciliation
+func setConfigMapCondition(grafana *aimsv1.Grafana, err error) {
+	condition := aimsv1.GrafanaCondition{
+		Type:    aimsv1.ConditionTypeGrafanaConfigMap,
+		Status:  aimsv1.ConditionStatusTrue,
+		Reason:  aimsv1.ConditionReasonGrafanaConfigMapUpdate,
+		Message: "configmaps are up to date",
+	}
+	if err != nil {
+		condition.Status = aimsv1.ConditionStatusFalse
+		condition.Reason = aimsv1.ConditionReasonGrafanaConfigMapUpdateFailed
+		condition.Message = err.Error()
+	}
+	conditions.Set(&grafana.Status.Conditions, condition)
+}
+
+// setDeploymentCondition records the outcome of the deployment reconciliation
+func setDeploymentCondition(grafana *aimsv1.Grafana, err error) {
+	condition := aimsv1.GrafanaCondition{
+		Type:    aimsv1.ConditionTypeGrafanaDeployment,
+		Status:  aimsv1.ConditionStatusTrue,
+		Reason:  aimsv1.ConditionReasonGrafanaDeploymentUpdate,
+		Message: "deployment is up to date",
+	}
+	if err != nil {
+		condition.Status = aimsv1.ConditionStatusFalse
+		condition.Reason = aimsv1.ConditionReasonGrafanaDeploymentUpdateFailed
+		condition.Message = err.Error()
+	}
+	conditions.Set(&grafana.Status.Conditions, condition)
+}
+
+// observeDeployment derives the Ready condition, mirrored in gStatus, from the available
+// replicas of the deployment and reports its replicas for the scale subresource. Status
+// changes of the deployment requeue the instance.
+func (c *Controller) observeDeployment(grafana *aimsv1.Grafana, name string) error {
+	grafana.Status.Selector = labels.SelectorFromSet(util.PodLabels(grafana)).String()
+
+	condition := aimsv1.GrafanaCondition{
+		Type:   aimsv1.ConditionTypeReady,
+		Status: aimsv1.ConditionStatusFalse,
+		Reason: aimsv1.ConditionReasonReplicasUnavailable,
+	}
+
+	deploy, err := c.deploymentLister.Deployments(grafana.Namespace).Get(name)
+	if err != nil && errors.IsNotFound(err) {
+		// Just created, the informer has not seen it yet
+		condition.Message = fmt.Sprintf("deployment %s is not created yet", name)
+	} else if err != nil {
+		return err
+	} else {
+		desired := int32(1)
+		if deploy.Spec.Replicas != nil {
+			desired = *deploy.Spec.Replicas
+		}
+		grafana.Status.Replicas = deploy.Status.Replicas
+		available := deploy.Status.AvailableReplicas
+		condition.Message = fmt.Sprintf("%d of %d replicas available", available, desired)
+		if deploy.Status.ObservedGeneration >= deploy.Generation && available >= desired && desired > 0 {
+			condition.Status = aimsv1.ConditionStatusTrue
+			condition.Reason = aimsv1.ConditionReasonReplicasAvailable
+		}
+	}
+
+	conditions.Set(&grafana.Status.Conditions, condition)
+	grafana.Status.GStatus = v1.ConditionStatus(condition.Status)
+	return nil
+}
+
+// setProgressConditions maintains the kstatus Reconciling and Stalled conditions. reason
+// tells why the reconcile ended: with a spec the user has to fix (invalid spec, ownership
+// conflict), with a transient error, or reconciled, in which case the instance is still
+// reconciling until it is Ready.
+func setProgressConditions(grafana *aimsv1.Grafana, reason aimsv1.ConditionReason, err error) {
+	reconciling := aimsv1.GrafanaCondition{
+		Type:   aimsv1.ConditionTypeReconciling,
+		Status: aimsv1.ConditionStatusFalse,
+		Reason: aimsv1.ConditionReasonReconciled,
+	}
+	stalled := aimsv1.GrafanaCondition{
+		Type:   aimsv1.ConditionTypeStalled,
+		Status: aimsv1.ConditionStatusFalse,
+		Reason: aimsv1.ConditionReasonReconciled,
+	}
+
+	switch reason {
+	case aimsv1.ConditionReasonInvalidSpec, aimsv1.ConditionReasonNotOwned:
+		stalled.Status = aimsv1.ConditionStatusTrue
+		stalled.Reason = reason
+		stalled.Message = err.Error()
+		reconciling.Reason = reason
+	case aimsv1.ConditionReasonReconcileError:
+		reconciling.Status = aimsv1.ConditionStatusTrue
+		reconciling.Reason = reason
+		reconciling.Message = err.Error()
+	default:
+		if ready := conditions.Find(grafana.Status.Conditions, aimsv1.ConditionTypeReady); ready != nil && ready.Status != aimsv1.ConditionStatusTrue {
+			reconciling.Status = aimsv1.ConditionStatusTrue
+			reconciling.Reason = ready.Reason
+			reconciling.Message = ready.Message
+		}
+	}
+
+	conditions.Set(&grafana.Status.Conditions, reconciling)
+	conditions.Set(&grafana.Status.Conditions, stalled)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	aimsv1 "github.com/dichque/grafana-operator/pkg/apis/grafana/v1"
+	"github.com/dichque/grafana-operator/pkg/conditions"
+)
+
+func TestSetProgressConditions(t *testing.T) {
+	ready := aimsv1.GrafanaCondition{Type: aimsv1.ConditionTypeReady, Status: aimsv1.ConditionStatusTrue, Reason: aimsv1.ConditionReasonReplicasAvailable}
+	unavailable := aimsv1.GrafanaCondition{
+		Type:    aimsv1.ConditionTypeReady,
+		Status:  aimsv1.ConditionStatusFalse,
+		Reason:  aimsv1.ConditionReasonReplicasUnavailable,
+		Message: "0 of 1 replicas available",
+	}
+	stalled := aimsv1.GrafanaCondition{Type: aimsv1.ConditionTypeStalled, Status: aimsv1.ConditionStatusTrue, Reason: aimsv1.ConditionReasonInvalidSpec}
+
+	tests := []struct {
+		name        string
+		conditions  []aimsv1.GrafanaCondition
+		reason      aimsv1.ConditionReason
+		err         error
+		reconciling aimsv1.GrafanaCondition
+		stalled     aimsv1.GrafanaCondition
+	}{
+		{
+			name:        "invalid spec",
+			reason:      aimsv1.ConditionReasonInvalidSpec,
+			err:         errors.New("spec.image: Required value"),
+			reconciling: aimsv1.GrafanaCondition{Status: aimsv1.ConditionStatusFalse, Reason: aimsv1.ConditionReasonInvalidSpec},
+			stalled:     aimsv1.GrafanaCondition{Status: aimsv1.ConditionStatusTrue, Reason: aimsv1.ConditionReasonInvalidSpec, Message: "spec.image: Required value"},
+		},
+		{
+			name:        "not owned",
+			conditions:  []aimsv1.GrafanaCondition{ready},
+			reason:      aimsv1.ConditionReasonNotOwned,
+			err:         errors.New("configmap a-config is not owned"),
+			reconciling: aimsv1.GrafanaCondition{Status: aimsv1.ConditionStatusFalse, Reason: aimsv1.ConditionReasonNotOwned},
+			stalled:     aimsv1.GrafanaCondition{Status: aimsv1.ConditionStatusTrue, Reason: aimsv1.ConditionReasonNotOwned, Message: "configmap a-config is not owned"},
+		},
+		{
+			name:        "transient error",
+			conditions:  []aimsv1.GrafanaCondition{ready},
+			reason:      aimsv1.ConditionReasonReconcileError,
+			err:         errors.New("connection refused"),
+			reconciling: aimsv1.GrafanaCondition{Status: aimsv1.ConditionStatusTrue, Reason: aimsv1.ConditionReasonReconcileError, Message: "connection refused"},
+			stalled:     aimsv1.GrafanaCondition{Status: aimsv1.ConditionStatusFalse, Reason: aimsv1.ConditionReasonReconciled},
+		},
+		{
+			name:        "reconciled and rolling out",
+			conditions:  []aimsv1.GrafanaCondition{unavailable},
+			reason:      aimsv1.ConditionReasonReconciled,
+			reconciling: aimsv1.GrafanaCondition{Status: aimsv1.ConditionStatusTrue, Reason: unavailable.Reason, Message: unavailable.Message},
+			stalled:     aimsv1.GrafanaCondition{Status: aimsv1.ConditionStatusFalse, Reason: aimsv1.ConditionReasonReconciled},
+		},
+		{
+			name:        "reconciled and ready",
+			conditions:  []aimsv1.GrafanaCondition{ready},
+			reason:      aimsv1.ConditionReasonReconciled,
+			reconciling: aimsv1.GrafanaCondition{Status: aimsv1.ConditionStatusFalse, Reason: aimsv1.ConditionReasonReconciled},
+			stalled:     aimsv1.GrafanaCondition{Status: aimsv1.ConditionStatusFalse, Reason: aimsv1.ConditionReasonReconciled},
+		},
+		{
+			name:        "fixed spec",
+			conditions:  []aimsv1.GrafanaCondition{ready, stalled},
+			reason:      aimsv1.ConditionReasonReconciled,
+			reconciling: aimsv1.GrafanaCondition{Status: aimsv1.ConditionStatusFalse, Reason: aimsv1.ConditionReasonReconciled},
+			stalled:     aimsv1.GrafanaCondition{Status: aimsv1.ConditionStatusFalse, Reason: aimsv1.ConditionReasonReconciled},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			grafana := &aimsv1.Grafana{Status: aimsv1.GrafanaStatus{Conditions: append([]aimsv1.GrafanaCondition{}, test.conditions...)}}
+			setProgressConditions(grafana, test.reason, test.err)
+
+			for conditionType, expected := range map[aimsv1.ConditionType]aimsv1.GrafanaCondition{
+				aimsv1.ConditionTypeReconciling: test.reconciling,
+				aimsv1.ConditionTypeStalled:     test.stalled,
+			} {
+				condition := conditions.Find(grafana.Status.Conditions, conditionType)
+				if condition == nil {
+					t.Fatalf("no %s condition in %v", conditionType, grafana.Status.Conditions)
+				}
+				if condition.Status != expected.Status || condition.Reason != expected.Reason || condition.Message != expected.Message {
+					t.Errorf("expected %s %s/%s %q, got %s/%s %q", conditionType, expected.Status, expected.Reason, expected.Message,
+						condition.Status, condition.Reason, condition.Message)
+				}
+			}
+		})
+	}
+}
@@ -2,7 +2,7 @@
 
 ROOT_PACKAGE="github.com/dichque/grafana-operator"
 CUSTOM_RESOURCE_NAME="grafana"
-CUSTOM_RESOURCE_VERSION="v1"
+CUSTOM_RESOURCE_VERSION="v1,v1beta2"
 GO111MODULE=off
 
 #go get -u k8s.io/code-generator/...